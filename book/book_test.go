@@ -0,0 +1,183 @@
+package book
+
+import (
+    "bytes"
+    "math/rand"
+    "strings"
+    "testing"
+    "goChess/chess"
+    "github.com/stretchr/testify/require"
+)
+
+// play returns the position after sans, the side to move and the last move.
+func play(t *testing.T, sans ...string) (*chess.Board, chess.PlayerType, chess.Move) {
+    board, toMove, err := chess.ParseFEN(chess.StartingFEN)
+    require.NoError(t, err)
+    var last chess.Move
+    for _, san := range sans {
+        last, err = board.ParseSAN(san, toMove)
+        require.NoError(t, err)
+        board, err = board.MakeMove(last)
+        require.NoError(t, err)
+        toMove = chess.Opponent(toMove)
+    }
+    return board, toMove, last
+}
+
+// Reference keys from the Polyglot book format specification.
+func TestKey(t *testing.T) {
+    board, toMove, last := play(t)
+    require.Equal(t, uint64(0x463b96181691fc9c), Key(board, toMove, last))
+
+    board, toMove, last = play(t, "e4")
+    require.Equal(t, uint64(0x823c9b50fd114196), Key(board, toMove, last))
+
+    board, toMove, last = play(t, "e4", "d5")
+    require.Equal(t, uint64(0x0756b94461c50fb0), Key(board, toMove, last))
+
+    board, toMove, last = play(t, "e4", "d5", "e5")
+    require.Equal(t, uint64(0x662fafb965db29d4), Key(board, toMove, last))
+
+    // the e5 pawn may take f5 en passant
+    board, toMove, last = play(t, "e4", "d5", "e5", "f5")
+    require.Equal(t, uint64(0x22a48b5a8e47ff78), Key(board, toMove, last))
+
+    board, toMove, last = play(t, "e4", "d5", "e5", "f5", "Ke2")
+    require.Equal(t, uint64(0x652a607ca3f242c1), Key(board, toMove, last))
+
+    board, toMove, last = play(t, "e4", "d5", "e5", "f5", "Ke2", "Kf7")
+    require.Equal(t, uint64(0x00fdd303c946bdd9), Key(board, toMove, last))
+}
+
+func TestMoveEncoding(t *testing.T) {
+    board, _, _ := play(t)
+    e4 := chess.NewMove(6, 4, 4, 4)
+    // e2e4: from square 12, to square 28
+    require.Equal(t, uint16(12<<6 | 28), EncodeMove(board, e4))
+    m, ok := DecodeMove(board, 12<<6 | 28)
+    require.True(t, ok)
+    require.Equal(t, e4, m)
+
+    castling, _, err := chess.ParseFEN("4k3/8/8/8/8/8/8/R3K2R w KQ - 0 1")
+    require.NoError(t, err)
+    short := chess.NewMove(7, 4, 7, 6)
+    require.Equal(t, uint16(4<<6 | 7), EncodeMove(castling, short))
+    m, ok = DecodeMove(castling, 4<<6 | 7)
+    require.True(t, ok)
+    require.Equal(t, short, m)
+    m, ok = DecodeMove(castling, 4<<6 | 0)
+    require.True(t, ok)
+    require.Equal(t, chess.NewMove(7, 4, 7, 2), m)
+
+    _, ok = DecodeMove(castling, 1<<12 | 4<<6 | 7)
+    require.False(t, ok)
+
+    promotion, _, err := chess.ParseFEN("4k3/1P6/8/8/8/8/8/4K3 w - - 0 1")
+    require.NoError(t, err)
+    b8q := promotion.LegalMoves(chess.PlayerWhite)[0]
+    require.Equal(t, "b7b8q", b8q.String())
+    // b7 is square 49, b8 square 57
+    require.Equal(t, uint16(4<<12 | 49<<6 | 57), EncodeMove(promotion, b8q))
+    m, ok = DecodeMove(promotion, 4<<12 | 49<<6 | 57)
+    require.True(t, ok)
+    require.Equal(t, b8q, m)
+}
+
+const games = `[Event "one"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 {main line} Nc6 (2... d6 3. d4) 3. Bb5 1-0
+
+[Event "two"]
+[Result "0-1"]
+
+1. e4 c5 ; the Sicilian
+2. Nf3 d6 0-1
+
+[Event "three"]
+
+1. d4 d5 $1 2. c4 *
+`
+
+func TestBuildFromPGN(t *testing.T) {
+    b, _, err := BuildFromPGN(strings.NewReader(games), BuildOptions{})
+    require.NoError(t, err)
+
+    board, toMove, last := play(t)
+    moves, entries := b.Moves(board, toMove, last)
+    require.Equal(t, []chess.Move{chess.NewMove(6, 4, 4, 4), chess.NewMove(6, 3, 4, 3)}, moves)
+    require.Equal(t, uint16(2), entries[0].Weight)
+    require.Equal(t, uint16(1), entries[1].Weight)
+
+    // the variation is not part of the book
+    board, toMove, last = play(t, "e4", "e5", "Nf3")
+    moves, _ = b.Moves(board, toMove, last)
+    require.Equal(t, []chess.Move{chess.NewMove(0, 1, 2, 2)}, moves)
+
+    board, toMove, last = play(t, "e4", "c5", "Nf3")
+    moves, _ = b.Moves(board, toMove, last)
+    require.Equal(t, []chess.Move{chess.NewMove(1, 3, 2, 3)}, moves)
+}
+
+func TestBuildFilters(t *testing.T) {
+    b, _, err := BuildFromPGN(strings.NewReader(games), BuildOptions{MinCount: 2})
+    require.NoError(t, err)
+    require.Equal(t, 1, b.Len())
+
+    b, _, err = BuildFromPGN(strings.NewReader(games), BuildOptions{MaxPly: 2})
+    require.NoError(t, err)
+    require.Equal(t, 5, b.Len())
+}
+
+func TestBuildSkipsEnPassant(t *testing.T) {
+    const enPassant = `[Event "en passant"]
+
+1. e4 d5 2. e5 f5 3. exf6 Nxf6 *
+`
+    b, stats, err := BuildFromPGN(strings.NewReader(games+"\n"+enPassant), BuildOptions{})
+    require.NoError(t, err)
+    require.Equal(t, BuildStats{Games: 4, Skipped: 1}, stats)
+
+    // the game is kept up to the capture
+    board, toMove, last := play(t, "e4", "d5", "e5")
+    moves, _ := b.Moves(board, toMove, last)
+    require.Equal(t, []chess.Move{chess.NewMove(1, 5, 3, 5)}, moves)
+    board, toMove, last = play(t, "e4", "d5", "e5", "f5")
+    require.Empty(t, b.Lookup(Key(board, toMove, last)))
+}
+
+func TestReadWrite(t *testing.T) {
+    b, _, err := BuildFromPGN(strings.NewReader(games), BuildOptions{})
+    require.NoError(t, err)
+
+    var buf bytes.Buffer
+    require.NoError(t, b.Write(&buf))
+    require.Equal(t, b.Len()*entrySize, buf.Len())
+
+    read, err := Read(&buf)
+    require.NoError(t, err)
+    require.Equal(t, b.entries, read.entries)
+
+    _, err = Read(bytes.NewReader(make([]byte, entrySize+1)))
+    require.ErrorIs(t, err, InvalidBookError)
+}
+
+func TestPick(t *testing.T) {
+    b, _, err := BuildFromPGN(strings.NewReader(games), BuildOptions{})
+    require.NoError(t, err)
+    board, toMove, last := play(t)
+
+    r := rand.New(rand.NewSource(7))
+    picked := map[chess.Move]int{}
+    for i := 0; i < 3000; i++ {
+        m, ok := b.Pick(board, toMove, last, r)
+        require.True(t, ok)
+        picked[m]++
+    }
+    require.InDelta(t, 2000, picked[chess.NewMove(6, 4, 4, 4)], 150)
+    require.InDelta(t, 1000, picked[chess.NewMove(6, 3, 4, 3)], 150)
+
+    board, toMove, last = play(t, "a3")
+    _, ok := b.Pick(board, toMove, last, r)
+    require.False(t, ok)
+}
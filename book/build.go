@@ -0,0 +1,173 @@
+package book
+
+import (
+    "bufio"
+    "io"
+    "regexp"
+    "strings"
+    "goChess/chess"
+)
+
+type BuildOptions struct {
+    // MaxPly limits how many half moves of every game go into the book,
+    // 0 for no limit.
+    MaxPly   int
+    // MinCount drops moves played fewer times than this in a position.
+    MinCount int
+}
+
+// BuildStats counts the games a book was built from.
+type BuildStats struct {
+    Games   int
+    // Skipped is the number of games left out, or cut short at the first
+    // move the board cannot play, such as an en passant capture.
+    Skipped int
+}
+
+// seen is a move played in the position with the given key.
+type seen struct {
+    key  uint64
+    move uint16
+}
+
+type pgnGame struct {
+    fen   string
+    moves []string
+}
+
+var pgnTag = regexp.MustCompile(`^\[(\w+)\s+"(.*)"\]$`)
+var moveNumber = regexp.MustCompile(`^\d+\.+`)
+
+// BuildFromPGN builds a book from the games of a PGN collection, weighting
+// every move by how often it was played. A game is only followed up to the
+// first move the board cannot play, and counted as skipped.
+func BuildFromPGN(r io.Reader, opts BuildOptions) (*Book, BuildStats, error) {
+    games, err := readPGN(r)
+    if err != nil {
+        return nil, BuildStats{}, err
+    }
+
+    counts := make(map[seen]int)
+    stats := BuildStats{Games: len(games)}
+    for _, g := range games {
+        if !countGame(g, opts, counts) {
+            stats.Skipped++
+        }
+    }
+
+    b := &Book{entries: make([]Entry, 0, len(counts))}
+    for s, n := range counts {
+        if n < opts.MinCount {
+            continue
+        }
+        if n > 0xFFFF {
+            n = 0xFFFF
+        }
+        b.entries = append(b.entries, Entry{Key: s.key, Move: s.move, Weight: uint16(n)})
+    }
+    b.sort()
+
+    return b, stats, nil
+}
+
+// countGame adds the moves of g to counts, and reports whether all of the
+// moves within opts.MaxPly could be played.
+func countGame(g pgnGame, opts BuildOptions, counts map[seen]int) bool {
+    board, toMove, err := chess.ParseFEN(g.fen)
+    if err != nil {
+        return false
+    }
+
+    var last chess.Move
+    for ply, san := range g.moves {
+        if opts.MaxPly > 0 && ply >= opts.MaxPly {
+            break
+        }
+        m, err := board.ParseSAN(san, toMove)
+        if err != nil {
+            return false
+        }
+        counts[seen{Key(board, toMove, last), EncodeMove(board, m)}]++
+        if board, err = board.MakeMove(m); err != nil {
+            return false
+        }
+        toMove, last = chess.Opponent(toMove), m
+    }
+
+    return true
+}
+
+// readPGN splits a PGN collection into games, keeping the SAN moves of the
+// main line and the starting position.
+func readPGN(r io.Reader) ([]pgnGame, error) {
+    games := make([]pgnGame, 0)
+    game := pgnGame{fen: chess.StartingFEN}
+    inMoves := false
+    depth := 0
+    inComment := false
+
+    flush := func() {
+        if len(game.moves) > 0 {
+            games = append(games, game)
+        }
+        game = pgnGame{fen: chess.StartingFEN}
+        inMoves = false
+    }
+
+    sc := bufio.NewScanner(r)
+    for sc.Scan() {
+        line := strings.TrimSpace(sc.Text())
+        if !inComment && depth == 0 {
+            if m := pgnTag.FindStringSubmatch(line); m != nil {
+                if inMoves {
+                    flush()
+                }
+                if m[1] == "FEN" {
+                    game.fen = m[2]
+                }
+                continue
+            }
+        }
+
+    tokens:
+        for _, tok := range pgnTokens(line) {
+            switch {
+            case inComment:
+                if strings.HasSuffix(tok, "}") {
+                    inComment = false
+                }
+            case strings.HasPrefix(tok, "{"):
+                inComment = !strings.HasSuffix(tok, "}")
+            case strings.HasPrefix(tok, ";"):
+                break tokens
+            case tok == "(":
+                depth++
+            case tok == ")":
+                depth--
+            case depth > 0, strings.HasPrefix(tok, "$"):
+            case tok == "1-0", tok == "0-1", tok == "1/2-1/2", tok == "*":
+                flush()
+            default:
+                san := moveNumber.ReplaceAllString(tok, "")
+                if san != "" {
+                    game.moves = append(game.moves, san)
+                    inMoves = true
+                }
+            }
+        }
+    }
+    if err := sc.Err(); err != nil {
+        return nil, err
+    }
+    flush()
+
+    return games, nil
+}
+
+func pgnTokens(line string) []string {
+    line = strings.ReplaceAll(line, "(", " ( ")
+    line = strings.ReplaceAll(line, ")", " ) ")
+    line = strings.ReplaceAll(line, "{", " {")
+    line = strings.ReplaceAll(line, "}", "} ")
+    return strings.Fields(line)
+}
@@ -0,0 +1,300 @@
+package book
+
+import (
+    "bufio"
+    "encoding/binary"
+    "fmt"
+    "io"
+    "math/rand"
+    "os"
+    "sort"
+    "goChess/chess"
+)
+
+var InvalidBookError = fmt.Errorf("Invalid Polyglot book")
+
+const entrySize = 16
+
+// Entry is a single record of a Polyglot book: a move played in the
+// position with the given key, and how often it should be chosen.
+type Entry struct {
+    Key    uint64
+    Move   uint16
+    Weight uint16
+    Learn  uint32
+}
+
+// Book is a Polyglot opening book held in memory, sorted by key.
+type Book struct {
+    entries []Entry
+}
+
+func Open(path string) (*Book, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    return Read(f)
+}
+
+func Read(r io.Reader) (*Book, error) {
+    br := bufio.NewReader(r)
+    entries := make([]Entry, 0)
+    var buf [entrySize]byte
+    for {
+        if _, err := io.ReadFull(br, buf[:]); err == io.EOF {
+            break
+        } else if err == io.ErrUnexpectedEOF {
+            return nil, fmt.Errorf("%w: size is not a multiple of %d bytes", InvalidBookError, entrySize)
+        } else if err != nil {
+            return nil, err
+        }
+
+        entries = append(entries, Entry{
+            Key:    binary.BigEndian.Uint64(buf[0:8]),
+            Move:   binary.BigEndian.Uint16(buf[8:10]),
+            Weight: binary.BigEndian.Uint16(buf[10:12]),
+            Learn:  binary.BigEndian.Uint32(buf[12:16]),
+        })
+    }
+
+    b := &Book{entries: entries}
+    b.sort()
+    return b, nil
+}
+
+func (b *Book) sort() {
+    sort.SliceStable(b.entries, func(i, j int) bool {
+        if b.entries[i].Key != b.entries[j].Key {
+            return b.entries[i].Key < b.entries[j].Key
+        }
+        return b.entries[i].Weight > b.entries[j].Weight
+    })
+}
+
+func (b *Book) Write(w io.Writer) error {
+    bw := bufio.NewWriter(w)
+    var buf [entrySize]byte
+    for _, e := range b.entries {
+        binary.BigEndian.PutUint64(buf[0:8], e.Key)
+        binary.BigEndian.PutUint16(buf[8:10], e.Move)
+        binary.BigEndian.PutUint16(buf[10:12], e.Weight)
+        binary.BigEndian.PutUint32(buf[12:16], e.Learn)
+        bw.Write(buf[:])
+    }
+    return bw.Flush()
+}
+
+func (b *Book) Len() int {
+    return len(b.entries)
+}
+
+// Lookup returns the entries of the position key, heaviest first.
+func (b *Book) Lookup(key uint64) []Entry {
+    i := sort.Search(len(b.entries), func(i int) bool {
+        return b.entries[i].Key >= key
+    })
+    j := i
+    for j < len(b.entries) && b.entries[j].Key == key {
+        j++
+    }
+
+    return b.entries[i:j]
+}
+
+// Moves returns the book moves of the position that are legal on board,
+// which last was played to reach. Polyglot books only hold positions of
+// standard sized boards.
+func (b *Book) Moves(board *chess.Board, toMove chess.PlayerType, last chess.Move) ([]chess.Move, []Entry) {
+    moves := make([]chess.Move, 0)
+    entries := make([]Entry, 0)
+    if board.Width() != chess.BoardSize || board.Height() != chess.BoardSize {
+        return moves, entries
+    }
+    legal := board.LegalMoves(toMove)
+    for _, e := range b.Lookup(Key(board, toMove, last)) {
+        m, ok := DecodeMove(board, e.Move)
+        if !ok {
+            continue
+        }
+        for _, l := range legal {
+            if l == m {
+                moves = append(moves, m)
+                entries = append(entries, e)
+                break
+            }
+        }
+    }
+
+    return moves, entries
+}
+
+// Pick chooses a book move at random, with the probability of every move
+// proportional to its weight.
+func (b *Book) Pick(board *chess.Board, toMove chess.PlayerType, last chess.Move, r *rand.Rand) (chess.Move, bool) {
+    moves, entries := b.Moves(board, toMove, last)
+    total := 0
+    for _, e := range entries {
+        total += int(e.Weight)
+    }
+    if total == 0 {
+        return chess.Move{}, false
+    }
+
+    n := r.Intn(total)
+    for i, e := range entries {
+        n -= int(e.Weight)
+        if n < 0 {
+            return moves[i], true
+        }
+    }
+
+    return chess.Move{}, false
+}
+
+// Key returns the Polyglot hash of the position. The board does not track
+// en passant, so it is taken from last, the move that reached the position,
+// or the zero Move if it is not known.
+func Key(board *chess.Board, toMove chess.PlayerType, last chess.Move) uint64 {
+    var key uint64
+    size := chess.BoardSize
+    for i := 0; i < size; i++ {
+        for j := 0; j < size; j++ {
+            p := board.GetPiece(i, j)
+            if p.Type() == chess.PieceNone {
+                continue
+            }
+            rank := size - 1 - i
+            key ^= polyglotRandom[64*pieceKind(p) + 8*rank + j]
+        }
+    }
+
+    for _, c := range board.CastlingRights() {
+        switch c {
+        case 'K':
+            key ^= polyglotRandom[768]
+        case 'Q':
+            key ^= polyglotRandom[769]
+        case 'k':
+            key ^= polyglotRandom[770]
+        case 'q':
+            key ^= polyglotRandom[771]
+        }
+    }
+
+    if file := enPassantFile(board, toMove, last); file >= 0 {
+        key ^= polyglotRandom[772 + file]
+    }
+
+    if toMove == chess.PlayerWhite {
+        key ^= polyglotRandom[780]
+    }
+
+    return key
+}
+
+// enPassantFile returns the file of the pawn last moved two squares, or -1.
+// As in Polyglot, it only counts if a pawn of toMove stands next to it and
+// could take it en passant.
+func enPassantFile(board *chess.Board, toMove chess.PlayerType, last chess.Move) int {
+    from, to := last.From(), last.To()
+    p := board.GetPiece(to.X(), to.Y())
+    if p.Type() != chess.PiecePawn || p.Player() == toMove || from.Y() != to.Y() || (from.X()-to.X())*(from.X()-to.X()) != 4 {
+        return -1
+    }
+
+    for _, y := range []int{to.Y()-1, to.Y()+1} {
+        if y < 0 || y >= chess.BoardSize {
+            continue
+        }
+        if q := board.GetPiece(to.X(), y); q.Type() == chess.PiecePawn && q.Player() == toMove {
+            return to.Y()
+        }
+    }
+
+    return -1
+}
+
+func pieceKind(p chess.Piece) int {
+    kind := 0
+    switch p.Type() {
+    case chess.PiecePawn:
+        kind = 0
+    case chess.PieceKnight:
+        kind = 1
+    case chess.PieceBishop:
+        kind = 2
+    case chess.PieceRook:
+        kind = 3
+    case chess.PieceQueen:
+        kind = 4
+    case chess.PieceKing:
+        kind = 5
+    }
+
+    if p.Player() == chess.PlayerWhite {
+        return 2*kind + 1
+    }
+    return 2*kind
+}
+
+// polyglotPromotions are the pieces a pawn promotes to, by the number
+// Polyglot gives them in bits 12 to 14 of a move.
+var polyglotPromotions = []chess.PieceType{chess.PieceNone, chess.PieceKnight, chess.PieceBishop, chess.PieceRook, chess.PieceQueen}
+
+// EncodeMove returns the Polyglot encoding of a move: the promotion piece,
+// and the target and origin squares as row*8+file from white's side.
+// Castling is encoded as the king taking its own rook.
+func EncodeMove(board *chess.Board, m chess.Move) uint16 {
+    size := chess.BoardSize
+    to := m.To()
+    toY := to.Y()
+    if board.IsCastle(m) && !board.Chess960() {
+        if toY > m.From().Y() {
+            toY = size - 1
+        } else {
+            toY = 0
+        }
+    }
+
+    promotion := 0
+    for i, t := range polyglotPromotions {
+        if m.Promotion() == t {
+            promotion = i
+        }
+    }
+
+    from := m.From()
+    return uint16(promotion) << 12 | uint16((size-1-from.X())*8 + from.Y()) << 6 | uint16((size-1-to.X())*8 + toY)
+}
+
+// DecodeMove turns a Polyglot move back into a board move. It fails for a
+// promotion of anything but a pawn, or to an unknown piece.
+func DecodeMove(board *chess.Board, pm uint16) (chess.Move, bool) {
+    size := chess.BoardSize
+    toY, toRank := int(pm & 7), int(pm >> 3 & 7)
+    fromY, fromRank := int(pm >> 6 & 7), int(pm >> 9 & 7)
+    promotion := int(pm >> 12 & 7)
+
+    fromX, toX := size-1-fromRank, size-1-toRank
+    piece := board.GetPiece(fromX, fromY)
+    if promotion != 0 {
+        if promotion >= len(polyglotPromotions) || piece.Type() != chess.PiecePawn {
+            return chess.Move{}, false
+        }
+        return chess.NewPromotion(fromX, fromY, toX, toY, polyglotPromotions[promotion]), true
+    }
+
+    target := board.GetPiece(toX, toY)
+    if piece.Type() == chess.PieceKing && target.Type() == chess.PieceRook && target.Player() == piece.Player() && !board.Chess960() {
+        if toY > fromY {
+            toY = fromY + 2
+        } else {
+            toY = fromY - 2
+        }
+    }
+
+    return chess.NewMove(fromX, fromY, toX, toY), true
+}
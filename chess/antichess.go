@@ -0,0 +1,110 @@
+package chess
+
+const ReasonNoPieces EndReason = "All pieces lost"
+
+
+// Antichess is chess where the goal is to lose every piece. Capturing is
+// compulsory, there is no check and no castling, and the king is a piece
+// like the others that pawns may also promote to. A player wins by losing
+// all pieces or by having no move to make.
+type Antichess struct {
+    Standard
+}
+
+func (Antichess) Name() string {
+    return "Antichess"
+}
+
+func (v Antichess) SetStartingPos(b *Board) error {
+    if err := v.Standard.SetStartingPos(b); err != nil {
+        return err
+    }
+    return b.SetCastlingRights("-")
+}
+
+// Validate checks that no pawn is on the first or last rank. Any number of
+// kings is allowed, none included.
+func (Antichess) Validate(b *Board, toMove PlayerType) error {
+    return b.validateMaterial(-1, -1)
+}
+
+func (Antichess) CanCapture(piece, target Piece) bool {
+    return true
+}
+
+func (Antichess) Legal(before, after *Board, m Move, player PlayerType) bool {
+    return true
+}
+
+// FilterMoves leaves out castling and keeps only the captures if player
+// has any.
+func (Antichess) FilterMoves(b *Board, player PlayerType, moves []Move) []Move {
+    mustCapture := b.canCapture(player)
+    kept := make([]Move, 0, len(moves))
+    for _, m := range moves {
+        if b.IsCastle(m) || mustCapture && !b.hasPiece(m.to.x, m.to.y) {
+            continue
+        }
+        kept = append(kept, m)
+    }
+    return kept
+}
+
+// ExtraMoves adds the promotions to a king.
+func (Antichess) ExtraMoves(b *Board, player PlayerType) []Move {
+    moves := make([]Move, 0)
+    for i := 0; i < b.height; i++ {
+        for j := 0; j < b.width; j++ {
+            if !b.isPieceOf(sqr(i, j), PiecePawn, player) {
+                continue
+            }
+            sel := b.selectPawn(i, j)
+            for _, m := range sel.moves() {
+                if m.Promotion() == PieceQueen {
+                    moves = append(moves, NewPromotion(m.from.x, m.from.y, m.to.x, m.to.y, PieceKing))
+                }
+            }
+        }
+    }
+    return moves
+}
+
+// canCapture reports whether any piece of player can take another piece.
+// Every move is legal in Antichess, so the pieces' own moves tell.
+func (b *Board) canCapture(player PlayerType) bool {
+    for i := 0; i < b.height; i++ {
+        for j := 0; j < b.width; j++ {
+            if p := b.GetPiece(i, j); !p.isPiece() || p.player != player {
+                continue
+            }
+            if sel, err := b.SelectPieceIgnoreCheck(i, j); err == nil && len(sel.threatenPieces) > 0 {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+func (Antichess) Outcome(b *Board, toMove PlayerType) (Outcome, bool) {
+    if b.pieceCount(toMove) == 0 {
+        return Outcome{Winner: toMove, Reason: ReasonNoPieces}, true
+    }
+    if len(b.LegalMoves(toMove)) == 0 {
+        return Outcome{Winner: toMove, Reason: ReasonStalemate}, true
+    }
+
+    return Outcome{}, false
+}
+
+// pieceCount returns the number of pieces player has on the board.
+func (b *Board) pieceCount(player PlayerType) int {
+    n := 0
+    for i := 0; i < b.height; i++ {
+        for j := 0; j < b.width; j++ {
+            if p := b.GetPiece(i, j); p.isPiece() && p.player == player {
+                n++
+            }
+        }
+    }
+    return n
+}
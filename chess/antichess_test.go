@@ -0,0 +1,73 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestAntichessCompulsoryCapture(t *testing.T) {
+    g, err := NewGame(Antichess{})
+    require.NoError(t, err)
+    require.Equal(t, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w - - 0 1", g.Board().FEN(g.ToMove()))
+    playSAN(t, g, "e4", "d5")
+
+    // exd5 is the only move once it is possible
+    require.Equal(t, []Move{NewMove(4, 4, 3, 3)}, g.LegalMoves())
+    sel, err := g.Board().SelectPiece(7, 6)
+    require.NoError(t, err)
+    require.Empty(t, sel.PossibleMoves())
+    sel, err = g.Board().SelectPiece(4, 4)
+    require.NoError(t, err)
+    require.Equal(t, []square{sqr(3, 3)}, sel.PossibleMoves())
+    _, err = g.Board().ParseSAN("Nf3", PlayerWhite)
+    require.ErrorIs(t, err, InvalidSANError)
+}
+
+func TestAntichessKing(t *testing.T) {
+    // the king can be captured, and walk into attack
+    g := gameFromFEN(t, Antichess{}, "8/8/8/8/8/3k4/8/R3K3 w - - 0 1")
+    require.Contains(t, g.LegalMoves(), NewMove(7, 4, 6, 3))
+    sel, err := g.Board().SelectPiece(7, 0)
+    require.NoError(t, err)
+    require.False(t, sel.Checking())
+
+    g = gameFromFEN(t, Antichess{}, "8/8/8/8/8/8/3k4/R3K3 w Q - 0 1")
+    require.Equal(t, []Move{NewMove(7, 4, 6, 3)}, g.LegalMoves())
+
+    // no castling, and no kings needed
+    g = gameFromFEN(t, Antichess{}, "8/8/8/8/8/8/7p/R3K3 w Q - 0 1")
+    require.NotContains(t, g.LegalMoves(), NewMove(7, 4, 7, 2))
+    gameFromFEN(t, Antichess{}, "8/8/8/8/8/8/7p/R7 w - - 0 1")
+}
+
+func TestAntichessPromotion(t *testing.T) {
+    g := gameFromFEN(t, Antichess{}, "8/P7/8/8/8/8/8/7k w - - 0 1")
+    require.Len(t, g.LegalMoves(), len(promotionPieces)+1)
+    require.Contains(t, g.LegalMoves(), NewPromotion(1, 0, 0, 0, PieceKing))
+
+    playSAN(t, g, "a8=K")
+    require.Equal(t, NewPiece(PieceKing, PlayerWhite), g.Board().GetPiece(0, 0))
+    require.Equal(t, "a7a8k", g.Moves()[0].String())
+
+    // every promotion has its own UCI name
+    g = gameFromFEN(t, Antichess{}, "8/P7/8/8/8/8/8/7k w - - 0 1")
+    names := map[string]bool{}
+    for _, m := range g.LegalMoves() {
+        names[m.String()] = true
+    }
+    require.Equal(t, map[string]bool{"a7a8q": true, "a7a8r": true, "a7a8b": true, "a7a8n": true, "a7a8k": true}, names)
+}
+
+func TestAntichessOutcome(t *testing.T) {
+    g := gameFromFEN(t, Antichess{}, "8/8/8/8/8/8/1p6/7R b - - 0 1")
+    playSAN(t, g, "b1=Q", "Rxb1")
+    outcome, over := g.Outcome()
+    require.True(t, over)
+    require.Equal(t, Outcome{Winner: PlayerBlack, Reason: ReasonNoPieces}, outcome)
+
+    // a blocked player wins
+    g = gameFromFEN(t, Antichess{}, "8/8/8/8/8/p7/P7/8 w - - 0 1")
+    outcome, over = g.Outcome()
+    require.True(t, over)
+    require.Equal(t, Outcome{Winner: PlayerWhite, Reason: ReasonStalemate}, outcome)
+}
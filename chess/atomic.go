@@ -0,0 +1,115 @@
+package chess
+
+import "fmt"
+
+const ReasonExplosion EndReason = "King exploded"
+
+// Atomic is chess where every capture is an explosion: the capturing and
+// captured pieces are removed along with every piece but a pawn on the
+// squares around. Kings can't capture, and exploding the enemy king wins.
+// Kings may stand next to each other, and are not in check while they do.
+type Atomic struct {
+    Standard
+}
+
+func (Atomic) Name() string {
+    return "Atomic"
+}
+
+func (Atomic) Validate(b *Board, toMove PlayerType) error {
+    if err := b.validateMaterial(1, 1); err != nil {
+        return err
+    }
+
+    if atomicInCheck(b, Opponent(toMove)) {
+        return fmt.Errorf("%w: %v is in check with %v to move", InvalidPositionError, Opponent(toMove), toMove)
+    }
+
+    return b.validateCastling()
+}
+
+func (Atomic) CanCapture(piece, target Piece) bool {
+    return piece.pieceType != PieceKing && target.pieceType != PieceKing
+}
+
+// Legal lets player make any move that blows up the enemy king, as long as
+// its own survives. Otherwise the move may not leave its king in check.
+func (Atomic) Legal(before, after *Board, m Move, player PlayerType) bool {
+    if !after.hasKing(player) {
+        return false
+    }
+    if !after.hasKing(Opponent(player)) {
+        return true
+    }
+
+    return !atomicInCheck(after, player)
+}
+
+func (v Atomic) ApplySpecialRules(before, after *Board, m Move) {
+    // a Chess960 king castles onto its own rook, which is no capture
+    captured := before.GetPiece(m.to.x, m.to.y)
+    if !captured.isPiece() || captured.player == before.GetPiece(m.from.x, m.from.y).player {
+        v.Standard.ApplySpecialRules(before, after, m)
+        return
+    }
+
+    after.pieces[m.to.x][m.to.y] = NoPiece()
+    for _, d := range kingSteps {
+        sq := sqr(m.to.x+d.x, m.to.y+d.y)
+        if after.inBounds(sq) && after.hasPiece(sq.x, sq.y) && after.GetPiece(sq.x, sq.y).pieceType != PiecePawn {
+            after.pieces[sq.x][sq.y] = NoPiece()
+            after.active[sq.x][sq.y] = true
+        }
+    }
+}
+
+func (Atomic) Outcome(b *Board, toMove PlayerType) (Outcome, bool) {
+    for _, player := range []PlayerType{toMove, Opponent(toMove)} {
+        if !b.hasKing(player) {
+            return Outcome{Winner: Opponent(player), Reason: ReasonExplosion}, true
+        }
+    }
+
+    if len(b.LegalMoves(toMove)) > 0 {
+        return Outcome{}, false
+    }
+    if atomicInCheck(b, toMove) {
+        return Outcome{Winner: Opponent(toMove), Reason: ReasonCheckmate}, true
+    }
+    return Outcome{Winner: PlayerNone, Reason: ReasonStalemate}, true
+}
+
+// atomicInCheck reports whether the king of player is attacked, which it
+// never is while touching the enemy king.
+func atomicInCheck(b *Board, player PlayerType) bool {
+    return !b.kingsTouch() && b.InCheck(player)
+}
+
+func (b *Board) hasKing(player PlayerType) bool {
+    for i := 0; i < b.height; i++ {
+        for j := 0; j < b.width; j++ {
+            if b.isPieceOf(sqr(i, j), PieceKing, player) {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// kingsTouch reports whether the white and black kings stand next to each
+// other.
+func (b *Board) kingsTouch() bool {
+    for i := 0; i < b.height; i++ {
+        for j := 0; j < b.width; j++ {
+            if !b.isPieceOf(sqr(i, j), PieceKing, PlayerWhite) {
+                continue
+            }
+            for _, d := range kingSteps {
+                if sq := sqr(i+d.x, j+d.y); b.inBounds(sq) && b.isPieceOf(sq, PieceKing, PlayerBlack) {
+                    return true
+                }
+            }
+        }
+    }
+    return false
+}
@@ -0,0 +1,63 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestAtomicExplosion(t *testing.T) {
+    g := gameFromFEN(t, Atomic{}, "4k3/8/2b1n3/3r4/2P1P3/8/8/4K3 w - - 0 1")
+    playSAN(t, g, "exd5")
+    // the pawn on c4 survives, pieces around d5 don't
+    require.Equal(t, "4k3/8/8/8/2P5/8/8/4K3 b - - 0 1", g.Board().FEN(g.ToMove()))
+
+    g = gameFromFEN(t, Atomic{}, "4k3/4q3/8/8/8/8/4R3/4K3 w - - 0 1")
+    playSAN(t, g, "Rxe7")
+    outcome, over := g.Outcome()
+    require.True(t, over)
+    require.Equal(t, Outcome{Winner: PlayerWhite, Reason: ReasonExplosion}, outcome)
+}
+
+func TestAtomicLegality(t *testing.T) {
+    // kings can't capture
+    g := gameFromFEN(t, Atomic{}, "4k3/8/8/8/8/8/3p4/4K3 w - - 0 1")
+    require.NotContains(t, g.LegalMoves(), NewMove(7, 4, 6, 3))
+    board, toMove, err := ParseFEN("4k3/8/8/8/8/8/3p4/4K3 w - - 0 1")
+    require.NoError(t, err)
+    require.Contains(t, board.LegalMoves(toMove), NewMove(7, 4, 6, 3))
+
+    // no capture may blow up the own king
+    g = gameFromFEN(t, Atomic{}, "k7/8/8/8/8/8/3nQ3/4K3 w - - 0 1")
+    require.NotContains(t, g.LegalMoves(), NewMove(6, 4, 6, 3))
+
+    // blowing up the enemy king wins even out of check
+    g = gameFromFEN(t, Atomic{}, "3nk3/8/8/8/7q/8/8/3RK3 w - - 0 1")
+    require.Contains(t, g.LegalMoves(), NewMove(7, 3, 0, 3))
+    playSAN(t, g, "Rxd8")
+    outcome, _ := g.Outcome()
+    require.Equal(t, PlayerWhite, outcome.Winner)
+}
+
+func TestAtomicKingsTouch(t *testing.T) {
+    g := gameFromFEN(t, Atomic{}, "8/8/8/8/8/3k4/8/4K3 w - - 0 1")
+    require.Contains(t, g.LegalMoves(), NewMove(7, 4, 6, 3))
+    playSAN(t, g, "Kd2")
+
+    // touching kings are out of reach of the queen's check
+    g = gameFromFEN(t, Atomic{}, "8/8/8/7q/8/3k4/3K4/8 w - - 0 1")
+    _, over := g.Outcome()
+    require.False(t, over)
+    require.Contains(t, g.LegalMoves(), NewMove(6, 3, 6, 4))
+    require.NotContains(t, g.LegalMoves(), NewMove(6, 3, 7, 3))
+
+    _, _, err := ParseFEN("8/8/8/8/8/3k4/3K4/8 w - - 0 1")
+    require.ErrorIs(t, err, InvalidPositionError)
+}
+
+func TestAtomicChess960Castling(t *testing.T) {
+    // the king castles onto its rook without blowing up
+    g := gameFromFEN(t, Atomic{}, "1r2k3/8/8/8/8/8/8/1R2K3 w Bb - 0 1")
+    require.Contains(t, g.LegalMoves(), NewMove(7, 4, 7, 1))
+    require.NoError(t, g.Play(NewMove(7, 4, 7, 1)))
+    require.Equal(t, "1r2k3/8/8/8/8/8/8/2KR4 b q - 0 1", g.Board().FEN(g.ToMove()))
+}
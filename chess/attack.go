@@ -0,0 +1,162 @@
+package chess
+
+var rookDirs = []square{
+    sqr(-1,  0),
+    sqr( 1,  0),
+    sqr( 0, -1),
+    sqr( 0,  1),
+}
+
+var bishopDirs = []square{
+    sqr(-1,  1),
+    sqr( 1,  1),
+    sqr( 1, -1),
+    sqr(-1, -1),
+}
+
+var knightJumps = []square{
+    sqr( 1, 2),
+    sqr(-1, 2),
+    sqr( 1,-2),
+    sqr(-1,-2),
+    sqr( 2, 1),
+    sqr( 2,-1),
+    sqr(-2, 1),
+    sqr(-2,-1),
+}
+
+var kingSteps = []square{
+    sqr(-1, -1),
+    sqr(-1,  0),
+    sqr(-1,  1),
+    sqr( 0, -1),
+    sqr( 0,  1),
+    sqr( 1, -1),
+    sqr( 1,  0),
+    sqr( 1,  1),
+}
+
+// Attackers returns the squares of all pieces of player that attack (x, y),
+// whether the square is empty or not.
+func (b *Board) Attackers(x, y int, player PlayerType) []square {
+    return b.attackers(x, y, player, false)
+}
+
+func (b *Board) IsAttacked(x, y int, player PlayerType) bool {
+    return len(b.attackers(x, y, player, true)) > 0
+}
+
+// AttackMap returns the number of pieces of player attacking every square.
+func (b *Board) AttackMap(player PlayerType) [][]int {
+    am := make([][]int, b.height)
+    for i := 0; i < b.height; i++ {
+        am[i] = make([]int, b.width)
+        for j := 0; j < b.width; j++ {
+            am[i][j] = len(b.attackers(i, j, player, false))
+        }
+    }
+
+    return am
+}
+
+// Hanging returns the pieces of player that are attacked by the opponent
+// and not defended by any piece of their own.
+func (b *Board) Hanging(player PlayerType) []square {
+    hanging := make([]square, 0)
+    for i := 0; i < b.height; i++ {
+        for j := 0; j < b.width; j++ {
+            p := b.GetPiece(i, j)
+            if !p.isPiece() || p.player != player || p.pieceType == PieceKing {
+                continue
+            }
+            if b.IsAttacked(i, j, Opponent(player)) && !b.IsAttacked(i, j, player) {
+                hanging = append(hanging, sqr(i, j))
+            }
+        }
+    }
+
+    return hanging
+}
+
+func (b *Board) attackers(x, y int, player PlayerType, firstOnly bool) []square {
+    found := make([]square, 0, 2)
+    add := func(sq square) bool {
+        found = append(found, sq)
+        return firstOnly
+    }
+
+    // pawns attack towards the opponent, so look for them one row back
+    pawnRow := x + 1
+    if player == PlayerBlack {
+        pawnRow = x - 1
+    }
+    for _, dy := range []int{-1, 1} {
+        sq := sqr(pawnRow, y+dy)
+        if b.inBounds(sq) && b.isPieceOf(sq, PiecePawn, player) && add(sq) {
+            return found
+        }
+    }
+
+    for _, d := range knightJumps {
+        sq := sqr(x+d.x, y+d.y)
+        if b.inBounds(sq) && b.GetPiece(sq.x, sq.y).player == player && movesLikeKnight(b.GetPiece(sq.x, sq.y).pieceType) && add(sq) {
+            return found
+        }
+    }
+
+    for _, d := range kingSteps {
+        sq := sqr(x+d.x, y+d.y)
+        if b.inBounds(sq) && b.isPieceOf(sq, PieceKing, player) && add(sq) {
+            return found
+        }
+    }
+
+    for _, d := range rookDirs {
+        if sq, ok := b.firstPieceInDir(x, y, d); ok && b.GetPiece(sq.x, sq.y).player == player && movesLikeRook(b.GetPiece(sq.x, sq.y).pieceType) && add(sq) {
+            return found
+        }
+    }
+
+    for _, d := range bishopDirs {
+        if sq, ok := b.firstPieceInDir(x, y, d); ok && b.GetPiece(sq.x, sq.y).player == player && movesLikeBishop(b.GetPiece(sq.x, sq.y).pieceType) && add(sq) {
+            return found
+        }
+    }
+
+    return found
+}
+
+func (b *Board) firstPieceInDir(x, y int, dir square) (square, bool) {
+    for i := 1; i < max(b.width, b.height); i++ {
+        sq := sqr(x+i*dir.x, y+i*dir.y)
+        if !b.inBounds(sq) {
+            return sq, false
+        }
+        if b.hasPiece(sq.x, sq.y) {
+            return sq, true
+        }
+    }
+
+    return sqr(-1, -1), false
+}
+
+// movesLikeRook reports whether pieces of type t slide along ranks and
+// files.
+func movesLikeRook(t PieceType) bool {
+    return t == PieceRook || t == PieceQueen || t == PieceChancellor
+}
+
+// movesLikeBishop reports whether pieces of type t slide along diagonals.
+func movesLikeBishop(t PieceType) bool {
+    return t == PieceBishop || t == PieceQueen || t == PieceArchbishop
+}
+
+// movesLikeKnight reports whether pieces of type t jump as a knight.
+func movesLikeKnight(t PieceType) bool {
+    return t == PieceKnight || t == PieceArchbishop || t == PieceChancellor
+}
+
+func (b *Board) isPieceOf(sq square, pieceType PieceType, player PlayerType) bool {
+    p := b.GetPiece(sq.x, sq.y)
+    return p.pieceType == pieceType && p.player == player
+}
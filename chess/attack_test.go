@@ -0,0 +1,86 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestAttackers(t *testing.T) {
+    board := NewChessBoard()
+    board.SetPiece(4, 4, NewPiece(PieceKnight, PlayerBlack))
+    board.SetPiece(4, 0, NewPiece(PieceRook, PlayerWhite))
+    board.SetPiece(5, 3, NewPiece(PiecePawn, PlayerWhite))
+    board.SetPiece(6, 2, NewPiece(PieceBishop, PlayerWhite))
+    board.SetPiece(6, 5, NewPiece(PieceKnight, PlayerWhite))
+    board.SetPiece(0, 4, NewPiece(PieceQueen, PlayerWhite))
+    board.SetPiece(2, 4, NewPiece(PiecePawn, PlayerBlack))
+
+    attackers := board.Attackers(4, 4, PlayerWhite)
+    require.Len(t, attackers, 3)
+    require.Contains(t, attackers, sqr(4, 0))
+    require.Contains(t, attackers, sqr(5, 3))
+    require.Contains(t, attackers, sqr(6, 5))
+
+    // the bishop is behind the pawn and the queen behind the black pawn
+    require.NotContains(t, attackers, sqr(6, 2))
+    require.NotContains(t, attackers, sqr(0, 4))
+
+    require.True(t, board.IsAttacked(4, 4, PlayerWhite))
+    require.False(t, board.IsAttacked(4, 4, PlayerBlack))
+    require.Empty(t, board.Attackers(4, 4, PlayerBlack))
+}
+
+func TestPawnAttacks(t *testing.T) {
+    board := NewChessBoard()
+    board.SetPiece(6, 0, NewPiece(PiecePawn, PlayerWhite))
+    board.SetPiece(1, 7, NewPiece(PiecePawn, PlayerBlack))
+
+    require.True(t, board.IsAttacked(5, 1, PlayerWhite))
+    require.False(t, board.IsAttacked(7, 1, PlayerWhite))
+    require.False(t, board.IsAttacked(5, 0, PlayerWhite))
+    require.True(t, board.IsAttacked(2, 6, PlayerBlack))
+    require.False(t, board.IsAttacked(0, 6, PlayerBlack))
+}
+
+func TestAttackMap(t *testing.T) {
+    board := NewChessBoard()
+    board.SetStartingPos()
+
+    am := board.AttackMap(PlayerWhite)
+    // every square of the third row from the bottom is attacked by a pawn
+    for j := 0; j < BoardSize; j++ {
+        require.Greater(t, am[5][j], 0)
+        require.Equal(t, 0, am[3][j])
+    }
+    // a3 is attacked by the b pawn and the knight
+    require.Equal(t, 2, am[5][0])
+
+    require.Empty(t, board.Hanging(PlayerWhite))
+    require.Empty(t, board.Hanging(PlayerBlack))
+}
+
+func TestHanging(t *testing.T) {
+    board := NewChessBoard()
+    board.SetPiece(3, 3, NewPiece(PieceKnight, PlayerBlack))
+    board.SetPiece(3, 6, NewPiece(PieceRook, PlayerBlack))
+    board.SetPiece(2, 2, NewPiece(PiecePawn, PlayerBlack))
+    board.SetPiece(5, 3, NewPiece(PieceRook, PlayerWhite))
+    board.SetPiece(6, 6, NewPiece(PieceRook, PlayerWhite))
+
+    hanging := board.Hanging(PlayerBlack)
+    require.Equal(t, []square{sqr(3, 6)}, hanging)
+}
+
+func TestInCheck(t *testing.T) {
+    board := NewChessBoard()
+    board.SetPiece(7, 4, NewPiece(PieceKing, PlayerWhite))
+    board.SetPiece(0, 4, NewPiece(PieceRook, PlayerBlack))
+    require.True(t, board.InCheck(PlayerWhite))
+    require.False(t, board.InCheck(PlayerBlack))
+
+    board.SetPiece(5, 4, NewPiece(PieceKnight, PlayerWhite))
+    require.False(t, board.InCheck(PlayerWhite))
+
+    board.SetPiece(6, 5, NewPiece(PiecePawn, PlayerBlack))
+    require.True(t, board.InCheck(PlayerWhite))
+}
@@ -2,82 +2,100 @@ package chess
 
 import "fmt"
 
+// BoardSize is the width and height of a standard board.
 const BoardSize = 8
+
+// MaxBoardSize is the most files and ranks a board can have.
+const MaxBoardSize = 12
+
+var InvalidBoardSizeError = fmt.Errorf("Invalid board size")
 var RepositionEmptySquareError = fmt.Errorf("No piece is selected")
 var RepositionPieceToSameSquareError = fmt.Errorf("Tried to reposition piece from one position to the same.")
+var NoStartingPosError = fmt.Errorf("No starting position for the board size")
 
 
 type Board struct {
+    width, height int
     pieces [][]Piece
+    // squares a move has left or entered, which takes away castling rights
     active [][]bool
+    chess960 bool
+    variant Variant
+    // checks given by white and black, for Three-check
+    checks [2]int
+    // captured pieces white and black may drop, and the squares of pieces
+    // that were promoted, for Crazyhouse
+    pockets [2][len(pocketPieces)]int
+    promoted [][]bool
 }
 
 func NewChessBoard() *Board {
-    pieces := make([][]Piece, BoardSize)
-    active := make([][]bool, BoardSize)
+    return newBoard(BoardSize, BoardSize)
+}
+
+// NewBoard returns an empty board width files wide and height ranks high.
+func NewBoard(width, height int) (*Board, error) {
+    if width < 1 || height < 1 || width > MaxBoardSize || height > MaxBoardSize {
+        return nil, fmt.Errorf("%w: %dx%d is not between 1x1 and %dx%d", InvalidBoardSizeError, width, height, MaxBoardSize, MaxBoardSize)
+    }
+
+    return newBoard(width, height), nil
+}
+
+func newBoard(width, height int) *Board {
+    pieces := make([][]Piece, height)
+    active := make([][]bool, height)
     for i, _ := range pieces {
-        pieces[i] = make([]Piece, BoardSize)
-        active[i] = make([]bool, BoardSize)
+        pieces[i] = make([]Piece, width)
+        active[i] = make([]bool, width)
         for j, _ := range pieces[i] {
             pieces[i][j] = NoPiece()
         }
     }
 
     return &Board{
+        width: width,
+        height: height,
         pieces: pieces,
         active: active,
     }
 }
 
-func (b *Board) setKingsInStartingPos() {
-    b.pieces[0][4] = NewPiece(PieceKing, PlayerBlack)
-    b.pieces[BoardSize-1][3] = NewPiece(PieceKing, PlayerWhite)
-}
-
-func (b *Board) setQueensInStartingPos() {
-    b.pieces[0][3] = NewPiece(PieceQueen, PlayerBlack)
-    b.pieces[BoardSize-1][4] = NewPiece(PieceQueen, PlayerWhite)
-}
-
-func (b *Board) setBishopsInStartingPos() {
-    b.pieces[0][2] = NewPiece(PieceBishop, PlayerBlack)
-    b.pieces[0][5] = NewPiece(PieceBishop, PlayerBlack)
-    b.pieces[BoardSize-1][2] = NewPiece(PieceBishop, PlayerWhite)
-    b.pieces[BoardSize-1][5] = NewPiece(PieceBishop, PlayerWhite)
-}
-
-func (b *Board) setKnightsInStartingPos() {
-    b.pieces[0][1] = NewPiece(PieceKnight, PlayerBlack)
-    b.pieces[0][6] = NewPiece(PieceKnight, PlayerBlack)
-    b.pieces[BoardSize-1][1] = NewPiece(PieceKnight, PlayerWhite)
-    b.pieces[BoardSize-1][6] = NewPiece(PieceKnight, PlayerWhite)
-}
-
-func (b *Board) setRooksInStartingPos() {
-    b.pieces[0][0] = NewPiece(PieceRook, PlayerBlack)
-    b.pieces[0][7] = NewPiece(PieceRook, PlayerBlack)
-    b.pieces[BoardSize-1][0] = NewPiece(PieceRook, PlayerWhite)
-    b.pieces[BoardSize-1][7] = NewPiece(PieceRook, PlayerWhite)
-}
-
 func (b *Board) SetPiece(x, y int, piece Piece) {
     b.pieces[x][y] = piece
 }
 
 func (b *Board) setPawnsInStartingPos() {
-    for i := 0; i < 8; i++ {
+    for i := 0; i < b.width; i++ {
         b.pieces[1][i] = NewPiece(PiecePawn, PlayerBlack)
-        b.pieces[6][i] = NewPiece(PiecePawn, PlayerWhite)
+        b.pieces[b.height-2][i] = NewPiece(PiecePawn, PlayerWhite)
     }
 }
 
-func (b *Board) SetStartingPos() {
-    b.setKingsInStartingPos()
-    b.setQueensInStartingPos()
-    b.setBishopsInStartingPos()
-    b.setKnightsInStartingPos()
-    b.setRooksInStartingPos()
+// backRanks are the pieces of the first rank in the starting position of
+// the boards that have one, by their width.
+var backRanks = map[int][]PieceType{
+    BoardSize: {PieceRook, PieceKnight, PieceBishop, PieceQueen, PieceKing, PieceBishop, PieceKnight, PieceRook},
+    10: {PieceRook, PieceKnight, PieceArchbishop, PieceBishop, PieceQueen, PieceKing, PieceBishop, PieceChancellor, PieceKnight, PieceRook},
+}
+
+// SetStartingPos sets up the pieces on their first two ranks: the standard
+// position on a board eight files wide and that of Capablanca chess on one
+// ten files wide. Other widths, and boards with fewer than four ranks, have
+// no starting position.
+func (b *Board) SetStartingPos() error {
+    row, ok := backRanks[b.width]
+    if !ok || b.height < 4 {
+        return fmt.Errorf("%w: %dx%d", NoStartingPosError, b.width, b.height)
+    }
+
+    for j, t := range row {
+        b.pieces[0][j] = NewPiece(t, PlayerBlack)
+        b.pieces[b.height-1][j] = NewPiece(t, PlayerWhite)
+    }
     b.setPawnsInStartingPos()
+
+    return nil
 }
 
 func (b *Board) repositionPiece(fromX, fromY, toX, toY int) (*Board, error) {
@@ -95,6 +113,11 @@ func (b *Board) repositionPiece(fromX, fromY, toX, toY int) (*Board, error) {
     nb.pieces[toX][toY] = p
 
     nb.active[fromX][fromY] = true
+    nb.active[toX][toY] = true
+    if nb.promoted != nil {
+        nb.promoted[toX][toY] = nb.promoted[fromX][fromY]
+        nb.promoted[fromX][fromY] = false
+    }
 
     return nb, nil
 }
@@ -108,49 +131,130 @@ func (b *Board) leftCastleAvailable(kingX, kingY int) bool {
 }
 
 func (b *Board) castleAvailable(kingX, kingY int, right bool) bool {
+    _, ok := b.castling(kingX, kingY, right)
+    return ok
+}
+
+// castlePlan says where the pieces of a castling move start and end.
+type castlePlan struct {
+    rookY, kingTo, rookTo int
+}
+
+// castling works out the castling move of the king towards one side. The
+// king and rook must not have moved, every square either of them crosses
+// must be empty but for the two of them, and the king may not castle out
+// of, through or into check. The king ends up on the second file from the
+// edge and the rook next to it on the inside: g and f or c and d on a
+// standard board. In standard chess the rook comes from the corner; in
+// Chess960 it is whichever one hasn't moved.
+func (b *Board) castling(kingX, kingY int, right bool) (castlePlan, bool) {
     if b.active[kingX][kingY] {
-        return false
+        return castlePlan{}, false
     }
 
     king := b.GetPiece(kingX, kingY)
-    if king.pieceType != PieceKing {
-        panic("Absurd board position :(")
+    if king.pieceType != PieceKing || kingX != b.homeRow(king.player) {
+        return castlePlan{}, false
     }
 
-    s := 1
-    t := kingY
-    p := b.GetPiece(kingX, 0)
+    dir := -1
     if right {
-        s = kingX + 1
-        t = BoardSize - 1
-        p = b.GetPiece(kingX, BoardSize - 1)
+        dir = 1
     }
 
-    if p.pieceType != PieceRook || p.player != king.player {
-        return false
+    plan := castlePlan{rookY: b.castleRookY(kingX, kingY, right), kingTo: 2, rookTo: 3}
+    if right {
+        plan.kingTo, plan.rookTo = b.width-2, b.width-3
     }
 
-    for i := s; i < t; i++ {
-        if b.hasPiece(kingX, i) {
-            return false
+    if plan.rookY < 0 || plan.kingTo < 0 || plan.kingTo >= b.width {
+        return castlePlan{}, false
+    }
+    if !b.chess960 && (plan.rookY-plan.kingTo)*dir <= 0 {
+        return castlePlan{}, false
+    }
+    for _, span := range [][2]int{{kingY, plan.kingTo}, {plan.rookY, plan.rookTo}} {
+        for y := min(span[0], span[1]); y <= max(span[0], span[1]); y++ {
+            if y != kingY && y != plan.rookY && b.hasPiece(kingX, y) {
+                return castlePlan{}, false
+            }
         }
-        if nb, err := b.repositionPiece(kingX, kingY, kingX, i); err != nil {
-            panic(fmt.Errorf("should be able to reposition the king for castle verification: %v", err))
-        } else if nb.InCheck(king.player) {
-            return false
+    }
+
+    nb := b.copy()
+    nb.pieces[kingX][kingY] = NoPiece()
+    nb.pieces[kingX][plan.rookY] = NoPiece()
+    for y := min(kingY, plan.kingTo); y <= max(kingY, plan.kingTo); y++ {
+        if nb.IsAttacked(kingX, y, Opponent(king.player)) {
+            return castlePlan{}, false
         }
     }
 
-    return true
+    return plan, true
+}
+
+// castleRookY finds the rook the king on (kingX, kingY) would castle with
+// towards one side, or returns -1. It is the rook in the corner in standard
+// chess and the first rook that hasn't moved in Chess960.
+func (b *Board) castleRookY(kingX, kingY int, right bool) int {
+    player := b.GetPiece(kingX, kingY).player
+    if !b.chess960 {
+        y := 0
+        if right {
+            y = b.width - 1
+        }
+        if b.isPieceOf(sqr(kingX, y), PieceRook, player) && !b.active[kingX][y] {
+            return y
+        }
+        return -1
+    }
+
+    dir := -1
+    if right {
+        dir = 1
+    }
+    for y := kingY + dir; y >= 0 && y < b.width; y += dir {
+        if b.isPieceOf(sqr(kingX, y), PieceRook, player) && !b.active[kingX][y] {
+            return y
+        }
+    }
+    return -1
+}
+
+// castleTarget is the square a castling king is moved to: where it ends up
+// in standard chess, onto its own rook in Chess960.
+func (b *Board) castleTarget(kingX, kingY int, right bool) square {
+    plan, _ := b.castling(kingX, kingY, right)
+    if b.chess960 {
+        return sqr(kingX, plan.rookY)
+    }
+    return sqr(kingX, plan.kingTo)
+}
+
+// IsCastle reports whether m is a castling move on this board.
+func (b *Board) IsCastle(m Move) bool {
+    if !b.isPieceOf(m.from, PieceKing, b.GetPiece(m.from.x, m.from.y).player) || m.from.x != m.to.x {
+        return false
+    }
+    if b.chess960 {
+        return b.isPieceOf(m.to, PieceRook, b.GetPiece(m.from.x, m.from.y).player)
+    }
+    dy := m.to.y - m.from.y
+    return dy > 1 || dy < -1
 }
 
 func (b *Board) copy() *Board {
     nPieces := make([][]Piece, len(b.pieces))
+    nActive := make([][]bool, len(b.active))
     for i := range b.pieces {
         nPieces[i] = make([]Piece, len(b.pieces[i]))
         copy(nPieces[i], b.pieces[i])
     }
-    return &Board{ pieces : nPieces }
+    for i := range b.active {
+        nActive[i] = make([]bool, len(b.active[i]))
+        copy(nActive[i], b.active[i])
+    }
+    return &Board{ width: b.width, height: b.height, pieces : nPieces, active: nActive, chess960: b.chess960, variant: b.variant, checks: b.checks, pockets: b.pockets, promoted: copyGrid(b.promoted) }
 }
 
 func (b *Board) GetPiece(x, y int) Piece {
@@ -166,11 +270,8 @@ func (b *Board) isActive(x, y int) bool {
 }
 
 func (b *Board) promotionNeeded(x, y int) bool {
-    if x > 0 && x < 7 {
-        return false
-    }
     p := b.GetPiece(x, y)
-    return p.pieceType == PiecePawn && ((p.player == PlayerWhite && x == 0) || (p.player == PlayerBlack && x == 7))
+    return p.pieceType == PiecePawn && x == b.homeRow(Opponent(p.player))
 }
 
 func (b *Board) promote(x, y int, newType PieceType) {
@@ -178,19 +279,60 @@ func (b *Board) promote(x, y int, newType PieceType) {
     b.SetPiece(x, y, NewPiece(newType, p.player))
 }
 
+// isPromotion reports whether m takes a pawn to its last rank.
+func (b *Board) isPromotion(m Move) bool {
+    p := b.GetPiece(m.from.x, m.from.y)
+    return p.pieceType == PiecePawn && m.to.x == b.homeRow(Opponent(p.player))
+}
+
+// completeMove names the queen in a promotion that names no piece.
+func (b *Board) completeMove(m Move) Move {
+    if m.promotion == "" && !m.IsDrop() && b.isPromotion(m) {
+        m.promotion = PieceQueen
+    }
+    return m
+}
+
+// makePromotion plays a promotion if it is one of the legal moves of the
+// pawn's player.
+func (b *Board) makePromotion(m Move) (*Board, error) {
+    for _, l := range b.LegalMoves(b.GetPiece(m.from.x, m.from.y).player) {
+        if l == m {
+            nb, err := b.repositionPiece(m.from.x, m.from.y, m.to.x, m.to.y)
+            if err != nil {
+                return nil, err
+            }
+            nb.applySpecialRules(b, m)
+            return nb, nil
+        }
+    }
+
+    return nil, IllegalMoveError
+}
+
+// MakeMove plays a legal move and returns the resulting board.
+func (b *Board) MakeMove(m Move) (*Board, error) {
+    if m.IsDrop() {
+        return b.makeDrop(m)
+    }
+    if m = b.completeMove(m); m.promotion != "" {
+        return b.makePromotion(m)
+    }
+
+    sel, err := b.SelectPiece(m.from.x, m.from.y)
+    if err != nil {
+        return nil, err
+    }
+
+    return sel.moveSelectedPiece(m.to.x, m.to.y)
+}
+
 func (b *Board) InCheck(player PlayerType) bool {
-    for i := 0; i < BoardSize; i++ {
-        for j := 0; j < BoardSize; j++ {
+    for i := 0; i < b.height; i++ {
+        for j := 0; j < b.width; j++ {
             piece := b.GetPiece(i, j)
-            if piece.isPiece() && piece.player != player {
-                sel, err := b.SelectPieceIgnoreCheck(i, j)
-                if err != nil {
-                    panic("cannot verify check")
-                }
-
-                if sel.checking {
-                    return true
-                }
+            if piece.pieceType == PieceKing && piece.player == player && b.IsAttacked(i, j, Opponent(player)) {
+                return true
             }
         }
     }
@@ -198,7 +340,32 @@ func (b *Board) InCheck(player PlayerType) bool {
     return false
 }
 
+// IsCheckmate reports whether player is in check and has no legal move.
+func (b *Board) IsCheckmate(player PlayerType) bool {
+    return b.InCheck(player) && len(b.LegalMoves(player)) == 0
+}
+
+// IsStalemate reports whether player is not in check but has no legal move.
+func (b *Board) IsStalemate(player PlayerType) bool {
+    return !b.InCheck(player) && len(b.LegalMoves(player)) == 0
+}
+
 func  (b *Board) SelectPiece(x, y int) (Select, error) {
+    sel, err := b.selectLegal(x, y)
+    if err != nil {
+        return Select{}, err
+    }
+
+    if !b.standardRules() {
+        sel.keepMoves(b.Variant().FilterMoves(b, sel.Piece().player, sel.moves()))
+    }
+
+    return sel, nil
+}
+
+// selectLegal selects the piece with the moves the variant allows it on its
+// own, before any rule that looks at all moves at once.
+func (b *Board) selectLegal(x, y int) (Select, error) {
     sel, err := b.SelectPieceIgnoreCheck(x, y)
     if err != nil {
         return Select{}, err
@@ -209,10 +376,9 @@ func  (b *Board) SelectPiece(x, y int) (Select, error) {
     return sel, nil
 }
 
-func (b *Board) applySpecialRules(sx, sy, tx, ty int) {
-    if b.promotionNeeded(tx, ty) {
-        b.promote(tx, ty, PieceQueen)
-    }
+// applySpecialRules lets the variant finish m, which turned before into b.
+func (b *Board) applySpecialRules(before *Board, m Move) {
+    b.Variant().ApplySpecialRules(before, b, m)
 }
 
 func  (b *Board) SelectPieceIgnoreCheck(x, y int) (Select, error) {
@@ -231,6 +397,10 @@ func  (b *Board) SelectPieceIgnoreCheck(x, y int) (Select, error) {
         sel = b.selectPawn(x, y)
     case PieceKnight:
         sel = b.selectKnight(x, y)
+    case PieceArchbishop:
+        sel = b.selectKnight(x, y).merge(b.selectBishop(x, y))
+    case PieceChancellor:
+        sel = b.selectKnight(x, y).merge(b.selectRook(x, y))
     default:
         return Select{}, EmptySquareSelectedError
     }
@@ -238,8 +408,34 @@ func  (b *Board) SelectPieceIgnoreCheck(x, y int) (Select, error) {
     return sel, nil
 }
 
+// Size returns the number of ranks of the board, which is also its number
+// of files on a square board. It is only meaningful for square boards.
+//
+// Deprecated: use Width and Height, which also fit rectangular boards.
 func (b *Board) Size() int {
-    return len(b.pieces)
+    return b.height
+}
+
+// Width returns the number of files of the board.
+func (b *Board) Width() int {
+    return b.width
+}
+
+// Height returns the number of ranks of the board.
+func (b *Board) Height() int {
+    return b.height
+}
+
+func (b *Board) inBounds(sq square) bool {
+    return sq.x >= 0 && sq.y >= 0 && sq.x < b.height && sq.y < b.width
+}
+
+// homeRow returns the row of the first rank of player.
+func (b *Board) homeRow(player PlayerType) int {
+    if player == PlayerBlack {
+        return 0
+    }
+    return b.height - 1
 }
 
 func (b *Board) SelectNone() Select {
@@ -307,7 +503,7 @@ func (b *Board) selectKnight(x, y int) Select {
 
     for _, d := range dir {
         sq := sqr(x+d.x, y+d.y)
-        if !sq.inBounds() {
+        if !b.inBounds(sq) {
             continue
         }
         if p := b.GetPiece(sq.x, sq.y); p.isPiece() {
@@ -342,26 +538,45 @@ func (b *Board) selectPawn(x, y int) Select {
     eatRight := sqr(x+dir, y+dir)
     eatLeft := sqr(x+dir, y-dir)
 
-    if !b.hasPiece(short.x, short.y) {
+    if b.inBounds(short) && !b.hasPiece(short.x, short.y) {
         sel.possibleMoves = append(sel.possibleMoves, short)
-        if ((selected.player == PlayerBlack && x==1) || (selected.player == PlayerWhite && x==BoardSize-2)) {
+        if b.doublePush(x, selected.player) && b.inBounds(long) {
             if !b.hasPiece(long.x, long.y) {
                 sel.possibleMoves = append(sel.possibleMoves, long)
             }
         }
     }
 
-    if p := b.GetPiece(eatRight.x, eatRight.y); p.isPiece() && p.player != selected.player {
-        sel.threat(eatRight)
+    if b.inBounds(eatRight) {
+        if p := b.GetPiece(eatRight.x, eatRight.y); p.isPiece() && p.player != selected.player {
+            sel.threat(eatRight)
+        }
     }
 
-    if p := b.GetPiece(eatLeft.x, eatLeft.y); p.isPiece() && p.player != selected.player {
-        sel.threat(eatLeft)
+    if b.inBounds(eatLeft) {
+        if p := b.GetPiece(eatLeft.x, eatLeft.y); p.isPiece() && p.player != selected.player {
+            sel.threat(eatLeft)
+        }
     }
 
     return sel
 }
 
+// doublePush reports whether a pawn of player on row x may move two
+// squares, which the variant decides by the rank counted from player's side.
+func (b *Board) doublePush(x int, player PlayerType) bool {
+    rank := b.height - x
+    if player == PlayerBlack {
+        rank = x + 1
+    }
+    for _, r := range b.Variant().DoublePushRanks(player) {
+        if r == rank {
+            return true
+        }
+    }
+    return false
+}
+
 func (b *Board) selectKing(x, y int) Select {
     selected := b.GetPiece(x, y)
 
@@ -376,7 +591,7 @@ func (b *Board) selectKing(x, y int) Select {
     for i := -1; i < 2; i++ {
         for j := -1; j < 2; j++ {
             sq := sqr(x+i, y+j)
-            if (i != 0 || j != 0) && sq.inBounds() {
+            if (i != 0 || j != 0) && b.inBounds(sq) {
                 p := b.GetPiece(x+i, y+j)
                 if p.isPiece() {
                     if p.player != selected.player {
@@ -390,11 +605,11 @@ func (b *Board) selectKing(x, y int) Select {
     }
 
     if b.rightCastleAvailable(x, y) {
-        sel.possibleCastle = append(sel.possibleCastle, sqr(x, y+2))
+        sel.possibleCastle = append(sel.possibleCastle, b.castleTarget(x, y, true))
     }
 
     if b.leftCastleAvailable(x, y) {
-        sel.possibleCastle = append(sel.possibleCastle, sqr(x, y-2))
+        sel.possibleCastle = append(sel.possibleCastle, b.castleTarget(x, y, false))
     }
 
     return sel
@@ -411,9 +626,9 @@ func (b *Board) selectRookOrBishopOrQueenByDirs(x, y int, dirs []square) Select
     }
 
     for _, dir := range dirs {
-        for i := 1; i < BoardSize; i++ {
+        for i := 1; i < max(b.width, b.height); i++ {
             sq := sqr(x+i*dir.x, y+i*dir.y)
-            if !sq.inBounds() {
+            if !b.inBounds(sq) {
                 continue
             }
             pc := b.GetPiece(sq.x, sq.y)
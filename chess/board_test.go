@@ -246,3 +246,61 @@ func TestPieceSelectionOnEmptyBoard(t *testing.T) {
         require.Len(t, sel.possibleMoves, 7*2 + 11)
     })
 }
+
+func TestNewBoard(t *testing.T) {
+    b, err := NewBoard(10, 8)
+    require.NoError(t, err)
+    require.Equal(t, 10, b.Width())
+    require.Equal(t, 8, b.Height())
+    require.Equal(t, BoardSize, NewChessBoard().Size())
+    require.True(t, b.inBounds(sqr(7, 9)))
+    require.False(t, b.inBounds(sqr(8, 0)))
+
+    _, err = NewBoard(0, 8)
+    require.ErrorIs(t, err, InvalidBoardSizeError)
+    _, err = NewBoard(8, MaxBoardSize+1)
+    require.ErrorIs(t, err, InvalidBoardSizeError)
+}
+
+func TestSetStartingPos(t *testing.T) {
+    b := NewChessBoard()
+    require.NoError(t, b.SetStartingPos())
+    require.Equal(t, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", b.FEN(PlayerWhite))
+
+    b, err := NewBoard(10, 8)
+    require.NoError(t, err)
+    require.NoError(t, b.SetStartingPos())
+    require.Equal(t, CapablancaFEN, b.FEN(PlayerWhite))
+
+    for _, size := range [][2]int{{6, 6}, {8, 3}, {12, 8}} {
+        b, err = NewBoard(size[0], size[1])
+        require.NoError(t, err)
+        require.ErrorIs(t, b.SetStartingPos(), NoStartingPosError)
+        require.Equal(t, "-", b.CastlingRights())
+    }
+
+    // the variants only set up boards of their own size
+    b, err = NewBoard(6, 6)
+    require.NoError(t, err)
+    require.ErrorIs(t, Standard{}.SetStartingPos(b), InvalidBoardSizeError)
+    require.ErrorIs(t, Capablanca{}.SetStartingPos(b), InvalidBoardSizeError)
+    require.ErrorIs(t, Horde{}.SetStartingPos(b), InvalidBoardSizeError)
+    require.ErrorIs(t, RacingKings{}.SetStartingPos(b), InvalidBoardSizeError)
+    require.ErrorIs(t, Gardner{}.SetStartingPos(b), InvalidBoardSizeError)
+    require.NoError(t, LosAlamos{}.SetStartingPos(b))
+}
+
+func TestCastleAfterBoardChanged(t *testing.T) {
+    board := NewChessBoard()
+    board.SetPiece(7, 4, NewPiece(PieceKing, PlayerWhite))
+    board.SetPiece(7, 7, NewPiece(PieceRook, PlayerWhite))
+    board.SetPiece(0, 4, NewPiece(PieceKing, PlayerBlack))
+    sel, err := board.SelectPiece(7, 4)
+    require.NoError(t, err)
+    require.Equal(t, []square{sqr(7, 6)}, sel.possibleCastle)
+
+    // the selection shares the board, which no longer has the king
+    board.SetPiece(7, 4, NoPiece())
+    _, err = sel.moveSelectedPiece(7, 6)
+    require.ErrorIs(t, err, IllegalMoveError)
+}
@@ -0,0 +1,23 @@
+package chess
+
+// CapablancaFEN is the starting position of Capablanca chess.
+const CapablancaFEN = "rnabqkbcnr/pppppppppp/10/10/10/10/PPPPPPPPPP/RNABQKBCNR w KQkq - 0 1"
+
+// Capablanca is chess on a board ten files wide, with an archbishop and a
+// chancellor for each side next to the bishops. Castling takes the king to
+// the c or i file and the rook next to it.
+type Capablanca struct {
+    Standard
+}
+
+func (Capablanca) Name() string {
+    return "Capablanca"
+}
+
+func (Capablanca) Dimensions() (width, height int) {
+    return 10, 8
+}
+
+func (Capablanca) SetStartingPos(b *Board) error {
+    return b.setStartingFEN(CapablancaFEN)
+}
@@ -0,0 +1,45 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestCapablancaStartingPos(t *testing.T) {
+    g, err := NewGame(Capablanca{})
+    require.NoError(t, err)
+    require.Equal(t, CapablancaFEN, g.Board().FEN(g.ToMove()))
+    // 20 pawn moves and 2 knight jumps for each knight, archbishop and
+    // chancellor
+    require.Len(t, g.LegalMoves(), 28)
+
+    g, err = NewGameFromFEN(Capablanca{}, CapablancaFEN)
+    require.NoError(t, err)
+    require.Equal(t, CapablancaFEN, g.Board().FEN(g.ToMove()))
+}
+
+func TestCapablancaPieces(t *testing.T) {
+    g, err := NewGame(Capablanca{})
+    require.NoError(t, err)
+    playSAN(t, g, "e4", "e5", "Ae2", "Ci6")
+    require.Equal(t, NewPiece(PieceArchbishop, PlayerWhite), g.Board().GetPiece(6, 4))
+    require.Equal(t, NewPiece(PieceChancellor, PlayerBlack), g.Board().GetPiece(2, 8))
+
+    // and then slide as a bishop and a rook
+    playSAN(t, g, "Ab5", "Ci4")
+    require.Equal(t, "a1c1", g.Board().UCI(NewMove(7, 0, 7, 2)))
+
+    g, err = NewGameFromFEN(Capablanca{}, "4k5/10/5A4/10/10/10/10/4K5 b - - 0 1")
+    require.NoError(t, err)
+    require.True(t, g.Board().InCheck(PlayerBlack))
+    g, err = NewGameFromFEN(Capablanca{}, "4k5/10/3C6/10/10/10/10/5K4 b - - 0 1")
+    require.NoError(t, err)
+    require.True(t, g.Board().InCheck(PlayerBlack))
+}
+
+func TestCapablancaCastling(t *testing.T) {
+    g, err := NewGameFromFEN(Capablanca{}, "r4k3r/10/10/10/10/10/10/R4K3R w KQkq - 0 1")
+    require.NoError(t, err)
+    playSAN(t, g, "O-O", "O-O-O")
+    require.Equal(t, "2kr5r/10/10/10/10/10/10/R6RK1 w - - 0 1", g.Board().FEN(g.ToMove()))
+}
@@ -0,0 +1,78 @@
+package chess
+
+import "fmt"
+
+var InvalidChess960PositionError = fmt.Errorf("Invalid Chess960 position")
+
+// Chess960Positions is the number of Chess960 starting positions.
+const Chess960Positions = 960
+
+// Chess960StandardPos is the number of the standard starting position.
+const Chess960StandardPos = 518
+
+// chess960Knights lists the two free squares, out of the five left after
+// placing the bishops and the queen, that the knights take for each of the
+// ten knight placements.
+var chess960Knights = [10][2]int{
+    {0, 1}, {0, 2}, {0, 3}, {0, 4}, {1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4},
+}
+
+// SetChess960Pos sets up the Chess960 starting position with the given
+// number, from 0 to 959 as in Scharnagl's numbering, and switches the board
+// to Chess960 castling.
+func (b *Board) SetChess960Pos(id int) error {
+    if id < 0 || id >= Chess960Positions {
+        return fmt.Errorf("%w: %d is not between 0 and %d", InvalidChess960PositionError, id, Chess960Positions-1)
+    }
+
+    if b.width != BoardSize {
+        return fmt.Errorf("%w: the board is %d files wide", InvalidChess960PositionError, b.width)
+    }
+
+    row := make([]PieceType, BoardSize)
+    row[2*(id%4)+1] = PieceBishop
+    id /= 4
+    row[2*(id%4)] = PieceBishop
+    id /= 4
+    placeOnFree(row, id%6, PieceQueen)
+    id /= 6
+    knights := chess960Knights[id]
+    placeOnFree(row, knights[1], PieceKnight)
+    placeOnFree(row, knights[0], PieceKnight)
+    placeOnFree(row, 0, PieceRook)
+    placeOnFree(row, 0, PieceKing)
+    placeOnFree(row, 0, PieceRook)
+
+    for j, t := range row {
+        b.pieces[0][j] = NewPiece(t, PlayerBlack)
+        b.pieces[b.height-1][j] = NewPiece(t, PlayerWhite)
+    }
+    b.setPawnsInStartingPos()
+    b.chess960 = true
+
+    return nil
+}
+
+// placeOnFree puts a piece on the n-th still empty square of row.
+func placeOnFree(row []PieceType, n int, t PieceType) {
+    for j := range row {
+        if row[j] != "" {
+            continue
+        }
+        if n == 0 {
+            row[j] = t
+            return
+        }
+        n--
+    }
+}
+
+// SetChess960 switches between standard and Chess960 castling. In Chess960
+// a castling move is given as the king moving onto its own rook.
+func (b *Board) SetChess960(chess960 bool) {
+    b.chess960 = chess960
+}
+
+func (b *Board) Chess960() bool {
+    return b.chess960
+}
@@ -0,0 +1,136 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func backRank(b *Board) string {
+    rank := ""
+    for j := 0; j < BoardSize; j++ {
+        rank += string(fenLetter(b.GetPiece(BoardSize-1, j)))
+    }
+    return rank
+}
+
+func TestSetChess960Pos(t *testing.T) {
+    for id, rank := range map[int]string{0: "BBQNNRKR", Chess960StandardPos: "RNBQKBNR", 959: "RKRNNQBB"} {
+        board := NewChessBoard()
+        require.NoError(t, board.SetChess960Pos(id))
+        require.True(t, board.Chess960())
+        require.Equal(t, rank, backRank(board))
+    }
+
+    seen := map[string]bool{}
+    for id := 0; id < Chess960Positions; id++ {
+        board := NewChessBoard()
+        require.NoError(t, board.SetChess960Pos(id))
+        rank := backRank(board)
+        require.False(t, seen[rank], rank)
+        seen[rank] = true
+
+        bishops, rooks, kingY := []int{}, []int{}, -1
+        for j, c := range rank {
+            switch c {
+            case 'B':
+                bishops = append(bishops, j)
+            case 'R':
+                rooks = append(rooks, j)
+            case 'K':
+                kingY = j
+            }
+            require.Equal(t, NewPiece(board.GetPiece(BoardSize-1, j).Type(), PlayerBlack), board.GetPiece(0, j))
+        }
+        require.NotEqual(t, bishops[0]%2, bishops[1]%2, rank)
+        require.True(t, rooks[0] < kingY && kingY < rooks[1], rank)
+        require.NotEmpty(t, board.LegalMoves(PlayerWhite), rank)
+    }
+
+    require.ErrorIs(t, NewChessBoard().SetChess960Pos(960), InvalidChess960PositionError)
+    require.ErrorIs(t, NewChessBoard().SetChess960Pos(-1), InvalidChess960PositionError)
+}
+
+func TestChess960Castling(t *testing.T) {
+    for _, fen := range []string{
+        "1r2k1r1/pppppppp/8/8/8/8/PPPPPPPP/1R2K1R1 w GBgb - 0 1",
+        "1r2k1r1/pppppppp/8/8/8/8/PPPPPPPP/1R2K1R1 w KQkq - 0 1",
+    } {
+        board, _, err := ParseFEN(fen)
+        require.NoError(t, err)
+        require.True(t, board.Chess960())
+        require.Equal(t, "KQkq", board.CastlingRights())
+
+        // the king takes its own rook to castle
+        require.True(t, board.IsCastle(NewMove(7, 4, 7, 6)))
+        nb, err := board.MakeMove(NewMove(7, 4, 7, 6))
+        require.NoError(t, err)
+        require.Equal(t, "1r2k1r1/pppppppp/8/8/8/8/PPPPPPPP/1R3RK1 b kq - 0 1", nb.FEN(PlayerBlack))
+
+        nb, err = board.MakeMove(NewMove(7, 4, 7, 1))
+        require.NoError(t, err)
+        require.Equal(t, "1r2k1r1/pppppppp/8/8/8/8/PPPPPPPP/2KR2R1 b kq - 0 1", nb.FEN(PlayerBlack))
+
+        m, err := board.ParseSAN("O-O-O", PlayerWhite)
+        require.NoError(t, err)
+        require.Equal(t, "e1b1", m.String())
+    }
+}
+
+func TestParseChess960FEN(t *testing.T) {
+    // in X-FEN, KQkq name the rooks in the corners whatever file the kings
+    // are on
+    fen := "rbbknnqr/pppppppp/8/8/8/8/PPPPPPPP/RBBKNNQR w KQkq - 0 1"
+    board, _, err := ParseChess960FEN(fen)
+    require.NoError(t, err)
+    require.True(t, board.Chess960())
+    require.Equal(t, "KQkq", board.CastlingRights())
+
+    // as standard chess, the kings may not castle from d1 and d8
+    _, _, err = ParseFEN(fen)
+    require.ErrorIs(t, err, InvalidPositionError)
+
+    // every starting position reads back as it was written
+    for id := 0; id < Chess960Positions; id++ {
+        board := NewChessBoard()
+        require.NoError(t, board.SetChess960Pos(id))
+        fen := board.FEN(PlayerWhite)
+
+        parsed, toMove, err := ParseChess960FEN(fen)
+        require.NoError(t, err, fen)
+        require.True(t, parsed.Chess960())
+        require.Equal(t, fen, parsed.FEN(toMove))
+        require.ElementsMatch(t, board.LegalMoves(PlayerWhite), parsed.LegalMoves(PlayerWhite), fen)
+    }
+}
+
+func TestChess960CastlingSquares(t *testing.T) {
+    for _, tc := range []struct {
+        fen  string
+        move Move
+        want string
+    }{
+        // the king only steps over onto the rook
+        {"3k4/8/8/8/8/8/8/5KR1 w G - 0 1", NewMove(7, 5, 7, 6), "3k4/8/8/8/8/8/8/5RK1 b - - 0 1"},
+        // the king stays where it is
+        {"3k4/8/8/8/8/8/8/6KR w H - 0 1", NewMove(7, 6, 7, 7), "3k4/8/8/8/8/8/8/5RK1 b - - 0 1"},
+        // the inner rook keeps the right, named by its file
+        {"3k4/8/8/8/8/8/8/RR2K3 w B - 0 1", NewMove(7, 4, 7, 1), "3k4/8/8/8/8/8/8/R1KR4 b - - 0 1"},
+    } {
+        board, _, err := ParseFEN(tc.fen)
+        require.NoError(t, err)
+        require.Contains(t, board.LegalMoves(PlayerWhite), tc.move, tc.fen)
+        nb, err := board.MakeMove(tc.move)
+        require.NoError(t, err)
+        require.Equal(t, tc.want, nb.FEN(PlayerBlack))
+    }
+
+    board, _, err := ParseFEN("3k4/8/8/8/8/8/8/RR2K3 w B - 0 1")
+    require.NoError(t, err)
+    require.Equal(t, "B", board.CastlingRights())
+
+    // f1 is attacked, so the king can't pass it
+    board, _, err = ParseFEN("k4r2/8/8/8/8/8/8/1R2K1R1 w GB - 0 1")
+    require.NoError(t, err)
+    require.NotContains(t, board.LegalMoves(PlayerWhite), NewMove(7, 4, 7, 6))
+    require.Contains(t, board.LegalMoves(PlayerWhite), NewMove(7, 4, 7, 1))
+}
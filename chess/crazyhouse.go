@@ -0,0 +1,234 @@
+package chess
+
+import (
+    "fmt"
+    "strings"
+)
+
+// pocketPieces are the pieces a pocket can hold, in the order they are
+// counted and written.
+var pocketPieces = [...]PieceType{PieceQueen, PieceRook, PieceBishop, PieceKnight, PiecePawn}
+
+// maxPocket is the most pieces of one type a pocket can hold: every piece
+// of a standard game but the kings.
+const maxPocket = 4*BoardSize - 2
+
+// Crazyhouse is standard chess where captured pieces change sides: they go
+// to the pocket of the capturer, who may drop them on an empty square
+// instead of moving. Pawns are not dropped on the first or last rank, and a
+// promoted piece goes back to being a pawn when captured.
+//
+// In FEN the pockets follow the placement in brackets ("[Qn]") and promoted
+// pieces are marked with a "~".
+type Crazyhouse struct {
+    Standard
+}
+
+func (Crazyhouse) Name() string {
+    return "Crazyhouse"
+}
+
+func (Crazyhouse) ExtraMoves(b *Board, player PlayerType) []Move {
+    return b.dropMoves(player)
+}
+
+func (v Crazyhouse) ApplySpecialRules(before, after *Board, m Move) {
+    if m.IsDrop() {
+        return
+    }
+
+    player := before.GetPiece(m.from.x, m.from.y).player
+    if captured := before.GetPiece(m.to.x, m.to.y); captured.isPiece() && captured.player != player {
+        if before.isPromoted(m.to.x, m.to.y) {
+            captured.pieceType = PiecePawn
+        }
+        after.AddToPocket(NewPiece(captured.pieceType, player))
+    }
+
+    if after.promotionNeeded(m.to.x, m.to.y) {
+        v.Standard.ApplySpecialRules(before, after, m)
+        after.setPromoted(m.to.x, m.to.y, true)
+    }
+}
+
+// ReadFEN takes the pockets, written in brackets after the placement or as
+// a ninth row, and the "~" marks of promoted pieces out of the FEN.
+func (Crazyhouse) ReadFEN(b *Board, fields []string) ([]string, error) {
+    if len(fields) == 0 {
+        return fields, nil
+    }
+
+    placement, pocket := fields[0], ""
+    if i := strings.IndexByte(placement, '['); i >= 0 {
+        if !strings.HasSuffix(placement, "]") {
+            return nil, fmt.Errorf("%w: unclosed pocket in %q", InvalidFENError, placement)
+        }
+        placement, pocket = placement[:i], placement[i+1:len(placement)-1]
+    } else if rows := strings.Split(placement, "/"); len(rows) == b.height+1 {
+        placement, pocket = strings.Join(rows[:b.height], "/"), rows[b.height]
+    }
+
+    for _, c := range pocket {
+        t, ok := fenPieces[toLower(c)]
+        if !ok || t == PieceKing {
+            return nil, fmt.Errorf("%w: bad piece %q in pocket", InvalidFENError, c)
+        }
+        player := PlayerBlack
+        if c != toLower(c) {
+            player = PlayerWhite
+        }
+        b.AddToPocket(NewPiece(t, player))
+    }
+
+    var sb strings.Builder
+    for i, row := range strings.Split(placement, "/") {
+        if i > 0 {
+            sb.WriteByte('/')
+        }
+        j := 0
+        for k := 0; k < len(row); k++ {
+            if n, size := fenRun(row[k:]); size > 0 {
+                j += n
+                sb.WriteString(row[k : k+size])
+                k += size - 1
+                continue
+            }
+            if row[k] == '~' {
+                if j == 0 || i >= b.height || j > b.width {
+                    return nil, fmt.Errorf("%w: promotion mark without a piece", InvalidFENError)
+                }
+                b.setPromoted(i, j-1, true)
+                continue
+            }
+            j++
+            sb.WriteByte(row[k])
+        }
+    }
+
+    rest := append([]string{sb.String()}, fields[1:]...)
+    return rest, nil
+}
+
+func (Crazyhouse) WriteFEN(b *Board, fields []string) []string {
+    fields[0] = b.fenPlacement(func(x, y int) string {
+        if b.isPromoted(x, y) {
+            return "~"
+        }
+        return ""
+    })
+
+    var sb strings.Builder
+    for _, player := range []PlayerType{PlayerWhite, PlayerBlack} {
+        for _, p := range b.Pocket(player) {
+            sb.WriteRune(fenLetter(p))
+        }
+    }
+    fields[0] += "[" + sb.String() + "]"
+
+    return fields
+}
+
+// Pocket returns the pieces player holds to drop, in the order of
+// pocketPieces.
+func (b *Board) Pocket(player PlayerType) []Piece {
+    pocket := make([]Piece, 0)
+    for t, n := range b.pockets[playerIndex(player)] {
+        for k := 0; k < n; k++ {
+            pocket = append(pocket, NewPiece(pocketPieces[t], player))
+        }
+    }
+    return pocket
+}
+
+// AddToPocket gives piece to the pocket of its player. Kings can't be held.
+func (b *Board) AddToPocket(piece Piece) {
+    if t := pocketIndex(piece.pieceType); t >= 0 {
+        b.pockets[playerIndex(piece.player)][t]++
+    }
+}
+
+func pocketIndex(t PieceType) int {
+    for i, pt := range pocketPieces {
+        if pt == t {
+            return i
+        }
+    }
+    return -1
+}
+
+func (b *Board) isPromoted(x, y int) bool {
+    return b.promoted != nil && b.promoted[x][y]
+}
+
+func (b *Board) setPromoted(x, y int, promoted bool) {
+    if b.promoted == nil {
+        if !promoted {
+            return
+        }
+        b.promoted = make([][]bool, b.height)
+        for i := range b.promoted {
+            b.promoted[i] = make([]bool, b.width)
+        }
+    }
+    b.promoted[x][y] = promoted
+}
+
+// dropMoves returns the legal drops of player: every piece type in its
+// pocket on every empty square, pawns kept off the first and last rank.
+func (b *Board) dropMoves(player PlayerType) []Move {
+    moves := make([]Move, 0)
+    for t, n := range b.pockets[playerIndex(player)] {
+        if n == 0 {
+            continue
+        }
+        for i := 0; i < b.height; i++ {
+            if pocketPieces[t] == PiecePawn && (i == 0 || i == b.height-1) {
+                continue
+            }
+            for j := 0; j < b.width; j++ {
+                if b.hasPiece(i, j) {
+                    continue
+                }
+                m := NewDrop(NewPiece(pocketPieces[t], player), i, j)
+                if b.Variant().Legal(b, b.drop(m), m, player) {
+                    moves = append(moves, m)
+                }
+            }
+        }
+    }
+    return moves
+}
+
+// drop returns the board after m, taking the piece out of the pocket.
+func (b *Board) drop(m Move) *Board {
+    nb := b.copy()
+    nb.SetPiece(m.to.x, m.to.y, m.drop)
+    nb.active[m.to.x][m.to.y] = true
+    nb.pockets[playerIndex(m.drop.player)][pocketIndex(m.drop.pieceType)]--
+    return nb
+}
+
+// makeDrop plays a drop if it is one of the legal moves of its player.
+func (b *Board) makeDrop(m Move) (*Board, error) {
+    for _, l := range b.LegalMoves(m.drop.player) {
+        if l == m {
+            nb := b.drop(m)
+            nb.applySpecialRules(b, m)
+            return nb, nil
+        }
+    }
+
+    return nil, IllegalMoveError
+}
+
+func copyGrid(grid [][]bool) [][]bool {
+    if grid == nil {
+        return nil
+    }
+    n := make([][]bool, len(grid))
+    for i := range grid {
+        n[i] = make([]bool, len(grid[i]))
+        copy(n[i], grid[i])
+    }
+    return n
+}
@@ -0,0 +1,85 @@
+package chess
+
+import (
+    "strings"
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestCrazyhouseCaptureAndDrop(t *testing.T) {
+    g, err := NewGame(Crazyhouse{})
+    require.NoError(t, err)
+    playSAN(t, g, "e4", "d5", "exd5", "Qxd5")
+    require.Equal(t, []Piece{NewPiece(PiecePawn, PlayerWhite)}, g.Board().Pocket(PlayerWhite))
+    require.Equal(t, []Piece{NewPiece(PiecePawn, PlayerBlack)}, g.Board().Pocket(PlayerBlack))
+
+    playSAN(t, g, "P@e4")
+    require.Equal(t, NewPiece(PiecePawn, PlayerWhite), g.Board().GetPiece(4, 4))
+    require.Empty(t, g.Board().Pocket(PlayerWhite))
+    require.Equal(t, "rnb1kbnr/ppp1pppp/8/3q4/4P3/8/PPPP1PPP/RNBQKBNR[p] b KQkq - 0 1", g.Board().FEN(g.ToMove()))
+
+    // the drop is a move like any other
+    require.Equal(t, "P@e4", g.Moves()[4].String())
+    require.ErrorIs(t, g.Play(NewDrop(NewPiece(PiecePawn, PlayerWhite), 4, 3)), IllegalMoveError)
+}
+
+func TestCrazyhouseDrops(t *testing.T) {
+    g := gameFromFEN(t, Crazyhouse{}, "4k3/8/8/8/8/8/8/4K3[Pn] w - - 0 1")
+    for _, m := range g.LegalMoves() {
+        if m.IsDrop() {
+            require.Equal(t, NewPiece(PiecePawn, PlayerWhite), m.Drop())
+            require.NotContains(t, []int{0, BoardSize - 1}, m.To().X())
+        }
+    }
+    require.Contains(t, g.LegalMoves(), NewDrop(NewPiece(PiecePawn, PlayerWhite), 1, 0))
+
+    // a drop can block a check
+    g = gameFromFEN(t, Crazyhouse{}, "4k3/8/8/8/8/8/8/r3K3[B] w - - 0 1")
+    require.Contains(t, g.LegalMoves(), NewDrop(NewPiece(PieceBishop, PlayerWhite), 7, 2))
+    require.NotContains(t, g.LegalMoves(), NewDrop(NewPiece(PieceBishop, PlayerWhite), 5, 2))
+
+    // and a drop can mate
+    g = gameFromFEN(t, Crazyhouse{}, "6rk/6pp/8/8/8/8/8/4K3[N] w - - 0 1")
+    playSAN(t, g, "N@f7")
+    outcome, over := g.Outcome()
+    require.True(t, over)
+    require.Equal(t, Outcome{Winner: PlayerWhite, Reason: ReasonCheckmate}, outcome)
+}
+
+func TestCrazyhousePromotedPieces(t *testing.T) {
+    g := gameFromFEN(t, Crazyhouse{}, "1n2k3/P7/8/8/8/8/8/4K3[] w - - 0 1")
+    playSAN(t, g, "axb8=Q")
+    require.Equal(t, "1Q~2k3/8/8/8/8/8/8/4K3[N] b - - 0 1", g.Board().FEN(g.ToMove()))
+
+    // a promoted piece goes back to a pawn when captured
+    g = gameFromFEN(t, Crazyhouse{}, "1Q~k5/8/8/8/8/8/8/4K3[N] b - - 0 1")
+    playSAN(t, g, "Kxb8")
+    require.Equal(t, []Piece{NewPiece(PiecePawn, PlayerBlack)}, g.Board().Pocket(PlayerBlack))
+}
+
+func TestCrazyhouseFEN(t *testing.T) {
+    for _, fen := range []string{
+        "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR[] w KQkq - 0 1",
+        "r1bqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKB1R[NNbp] b KQkq - 0 1",
+    } {
+        g := gameFromFEN(t, Crazyhouse{}, fen)
+        require.Equal(t, fen, g.Board().FEN(g.ToMove()))
+    }
+
+    g := gameFromFEN(t, Crazyhouse{}, "4k3/8/8/8/8/8/8/4K3/Qn w - - 0 1")
+    require.Equal(t, "4k3/8/8/8/8/8/8/4K3[Qn] w - - 0 1", g.Board().FEN(g.ToMove()))
+
+    _, err := NewGameFromFEN(Crazyhouse{}, "4k3/8/8/8/8/8/8/4K3[K] w - - 0 1")
+    require.ErrorIs(t, err, InvalidFENError)
+}
+
+func TestCrazyhousePocketHash(t *testing.T) {
+    // every count up to a full pocket hashes differently
+    seen := map[uint64]int{}
+    for n := 0; n <= maxPocket; n++ {
+        g := gameFromFEN(t, Crazyhouse{}, "4k3/8/8/8/8/8/8/4K3["+strings.Repeat("P", n)+"] w - - 0 1")
+        h := g.Board().Hash(PlayerWhite)
+        require.NotContains(t, seen, h, n)
+        seen[h] = n
+    }
+}
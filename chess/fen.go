@@ -0,0 +1,376 @@
+package chess
+
+import (
+    "fmt"
+    "strings"
+)
+
+var InvalidFENError = fmt.Errorf("Invalid FEN")
+
+const StartingFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+var fenPieces = map[rune]PieceType{
+    'p': PiecePawn,
+    'n': PieceKnight,
+    'b': PieceBishop,
+    'r': PieceRook,
+    'q': PieceQueen,
+    'k': PieceKing,
+    'a': PieceArchbishop,
+    'c': PieceChancellor,
+}
+
+// FENExtension is implemented by variants that keep more in a position than
+// the standard FEN fields can hold.
+type FENExtension interface {
+    // ReadFEN takes the parts the variant adds out of the fields of a FEN,
+    // stores them on b and returns the standard fields.
+    ReadFEN(b *Board, fields []string) ([]string, error)
+
+    // WriteFEN adds the parts the variant keeps to the standard fields.
+    WriteFEN(b *Board, fields []string) []string
+}
+
+// ParseFEN reads a position in Forsyth-Edwards Notation and returns the
+// board and the player to move. Castling rights are kept by marking the
+// squares of the kings and rooks that may no longer castle as active. The
+// en passant square and the move counters are not used by the board and
+// are ignored. Castling fields in Shredder-FEN, or in X-FEN naming rooks
+// off the corners, switch the board to Chess960.
+func ParseFEN(fen string) (*Board, PlayerType, error) {
+    return parseFEN(fen, Standard{}, false)
+}
+
+// ParseChess960FEN reads a Chess960 position in FEN. Unlike ParseFEN, it
+// takes "KQkq" as X-FEN even when the rooks stand in the corners and the
+// kings don't, as in many starting positions.
+func ParseChess960FEN(fen string) (*Board, PlayerType, error) {
+    return parseFEN(fen, Standard{}, true)
+}
+
+// parseFEN reads a position of the variant v, with Chess960 castling if
+// chess960 is set or the castling field asks for it.
+func parseFEN(fen string, v Variant, chess960 bool) (*Board, PlayerType, error) {
+    b := newBoard(v.Dimensions())
+    b.SetVariant(v)
+
+    fields := strings.Fields(fen)
+    if ext, ok := v.(FENExtension); ok {
+        var err error
+        if fields, err = ext.ReadFEN(b, fields); err != nil {
+            return nil, PlayerNone, err
+        }
+    }
+    if len(fields) < 2 {
+        return nil, PlayerNone, fmt.Errorf("%w: expected at least 2 fields in %q", InvalidFENError, fen)
+    }
+
+    if err := b.setPlacement(fields[0]); err != nil {
+        return nil, PlayerNone, err
+    }
+
+    var toMove PlayerType
+    switch fields[1] {
+    case "w":
+        toMove = PlayerWhite
+    case "b":
+        toMove = PlayerBlack
+    default:
+        return nil, PlayerNone, fmt.Errorf("%w: unknown side to move %q", InvalidFENError, fields[1])
+    }
+
+    castling := "-"
+    if len(fields) > 2 {
+        castling = fields[2]
+    }
+    b.chess960 = chess960 || b.isChess960Castling(castling)
+    if err := b.SetCastlingRights(castling); err != nil {
+        return nil, PlayerNone, err
+    }
+    if err := b.Validate(toMove); err != nil {
+        return nil, PlayerNone, fmt.Errorf("%w: %w", InvalidFENError, err)
+    }
+
+    return b, toMove, nil
+}
+
+// fenRun reads the run of empty squares s starts with, if any, and returns
+// its length and the number of digits it takes. Runs take two digits on
+// boards wider than nine files.
+func fenRun(s string) (n, size int) {
+    for size < len(s) && s[size] >= '0' && s[size] <= '9' {
+        n = 10*n + int(s[size]-'0')
+        size++
+    }
+    return n, size
+}
+
+// setStartingFEN puts the pieces of the starting position fen of a variant
+// on the board, which must be of the variant's size.
+func (b *Board) setStartingFEN(fen string) error {
+    placement := fen[:strings.IndexByte(fen, ' ')]
+    if err := b.setPlacement(placement); err != nil {
+        return fmt.Errorf("%w: %q does not fit a %dx%d board", InvalidBoardSizeError, placement, b.width, b.height)
+    }
+    return nil
+}
+
+// setPlacement puts the pieces of the placement field of a FEN on the board.
+func (b *Board) setPlacement(placement string) error {
+    rows := strings.Split(placement, "/")
+    if len(rows) != b.height {
+        return fmt.Errorf("%w: expected %d rows, got %d", InvalidFENError, b.height, len(rows))
+    }
+    for i, row := range rows {
+        j := 0
+        for k := 0; k < len(row); k++ {
+            if n, size := fenRun(row[k:]); size > 0 {
+                j += n
+                k += size - 1
+                continue
+            }
+
+            c := rune(row[k])
+            t, ok := fenPieces[toLower(c)]
+            if !ok {
+                return fmt.Errorf("%w: unknown piece %q", InvalidFENError, c)
+            }
+            if j >= b.width {
+                return fmt.Errorf("%w: row %d has more than %d squares", InvalidFENError, i+1, b.width)
+            }
+            player := PlayerBlack
+            if c != toLower(c) {
+                player = PlayerWhite
+            }
+            b.pieces[i][j] = NewPiece(t, player)
+            j++
+        }
+        if j != b.width {
+            return fmt.Errorf("%w: row %d does not have %d squares", InvalidFENError, i+1, b.width)
+        }
+    }
+
+    return nil
+}
+
+func toLower(c rune) rune {
+    if c >= 'A' && c <= 'Z' {
+        return c - 'A' + 'a'
+    }
+    return c
+}
+
+// SetCastlingRights takes the rights away from every rook not named in
+// castling, given in FEN notation ("KQkq", "-" for none). Rooks can also be
+// named by their file as in Shredder-FEN ("HAha"); in Chess960 "K" and "Q"
+// name the outermost rook on that side of the king, as in X-FEN.
+func (b *Board) SetCastlingRights(castling string) error {
+    rights := map[square]bool{}
+    if castling != "-" {
+        for _, c := range castling {
+            sq, ok := b.castlingRook(c)
+            if !ok {
+                return fmt.Errorf("%w: unknown castling right %q", InvalidFENError, c)
+            }
+            rights[sq] = true
+        }
+    }
+
+    for _, player := range []PlayerType{PlayerWhite, PlayerBlack} {
+        row := b.homeRow(player)
+        for j := 0; j < b.width; j++ {
+            sq := sqr(row, j)
+            corner := j == 0 || j == b.width-1
+            if (corner && !b.chess960 || b.isPieceOf(sq, PieceRook, player)) && !rights[sq] {
+                b.active[row][j] = true
+            }
+        }
+    }
+
+    return nil
+}
+
+// castlingRook returns the square of the rook a castling right refers to.
+func (b *Board) castlingRook(c rune) (square, bool) {
+    player, row := PlayerWhite, b.homeRow(PlayerWhite)
+    if c == toLower(c) {
+        player, row = PlayerBlack, b.homeRow(PlayerBlack)
+    }
+
+    switch {
+    case c >= 'A' && c < 'A'+rune(b.width):
+        return sqr(row, int(c-'A')), true
+    case c >= 'a' && c < 'a'+rune(b.width):
+        return sqr(row, int(c-'a')), true
+    case toLower(c) != 'k' && toLower(c) != 'q':
+        return square{}, false
+    }
+
+    right := c == 'K' || c == 'k'
+    corner := sqr(row, 0)
+    if right {
+        corner = sqr(row, b.width-1)
+    }
+    if !b.chess960 {
+        return corner, true
+    }
+    if y := b.outermostRookY(row, player, right); y >= 0 {
+        return sqr(row, y), true
+    }
+    return corner, true
+}
+
+// outermostRookY returns the file of the rook of player furthest from the
+// middle on one side of its king, or -1.
+func (b *Board) outermostRookY(row int, player PlayerType, right bool) int {
+    kingY := -1
+    for j := 0; j < b.width; j++ {
+        if b.isPieceOf(sqr(row, j), PieceKing, player) {
+            kingY = j
+        }
+    }
+    if kingY < 0 {
+        return -1
+    }
+
+    start, dir := 0, 1
+    if right {
+        start, dir = b.width-1, -1
+    }
+    for y := start; y != kingY; y += dir {
+        if b.isPieceOf(sqr(row, y), PieceRook, player) {
+            return y
+        }
+    }
+    return -1
+}
+
+// isChess960Castling reports whether a castling field only makes sense in
+// Chess960: it names rooks by file, or it names a side whose corner holds
+// no rook while another rook is there to castle with.
+func (b *Board) isChess960Castling(castling string) bool {
+    for _, c := range castling {
+        player := PlayerWhite
+        if c == toLower(c) {
+            player = PlayerBlack
+        }
+        row := b.homeRow(player)
+        switch toLower(c) {
+        case 'k', 'q':
+            right := toLower(c) == 'k'
+            corner := 0
+            if right {
+                corner = b.width - 1
+            }
+            if !b.isPieceOf(sqr(row, corner), PieceRook, player) && b.outermostRookY(row, player, right) >= 0 {
+                return true
+            }
+        case '-':
+        default:
+            return true
+        }
+    }
+    return false
+}
+
+// FEN returns the position in Forsyth-Edwards Notation with toMove to play.
+func (b *Board) FEN(toMove PlayerType) string {
+    side := "w"
+    if toMove == PlayerBlack {
+        side = "b"
+    }
+
+    fields := []string{b.fenPlacement(nil), side, b.CastlingRights(), "-", "0", "1"}
+    if ext, ok := b.Variant().(FENExtension); ok {
+        fields = ext.WriteFEN(b, fields)
+    }
+
+    return strings.Join(fields, " ")
+}
+
+// fenPlacement writes the piece placement field of FEN, with whatever mark
+// returns written after each piece.
+func (b *Board) fenPlacement(mark func(x, y int) string) string {
+    var sb strings.Builder
+    for i := 0; i < b.height; i++ {
+        empty := 0
+        for j := 0; j < b.width; j++ {
+            p := b.GetPiece(i, j)
+            if !p.isPiece() {
+                empty++
+                continue
+            }
+            if empty > 0 {
+                fmt.Fprint(&sb, empty)
+                empty = 0
+            }
+            sb.WriteRune(fenLetter(p))
+            if mark != nil {
+                sb.WriteString(mark(i, j))
+            }
+        }
+        if empty > 0 {
+            fmt.Fprint(&sb, empty)
+        }
+        if i < b.height-1 {
+            sb.WriteByte('/')
+        }
+    }
+
+    return sb.String()
+}
+
+func fenLetter(p Piece) rune {
+    for c, t := range fenPieces {
+        if t == p.pieceType {
+            if p.player == PlayerWhite {
+                return c - 'a' + 'A'
+            }
+            return c
+        }
+    }
+    return '?'
+}
+
+// eachCastlingRight calls f with the file of every rook player may still
+// castle with, kingside first: neither it nor the king has moved.
+func (b *Board) eachCastlingRight(player PlayerType, f func(rookY int, right bool)) {
+    row := b.homeRow(player)
+    for j := 0; j < b.width; j++ {
+        if !b.isPieceOf(sqr(row, j), PieceKing, player) || b.active[row][j] {
+            continue
+        }
+        for _, right := range []bool{true, false} {
+            if y := b.castleRookY(row, j, right); y >= 0 {
+                f(y, right)
+            }
+        }
+    }
+}
+
+// CastlingRights returns the castling rights in FEN notation, "-" if none.
+// In Chess960 a rook that isn't the outermost one on its side is named by
+// its file.
+func (b *Board) CastlingRights() string {
+    rights := ""
+    for _, player := range []PlayerType{PlayerWhite, PlayerBlack} {
+        b.eachCastlingRight(player, func(rookY int, right bool) {
+            c := 'Q'
+            if right {
+                c = 'K'
+            }
+            if b.chess960 && rookY != b.outermostRookY(b.homeRow(player), player, right) {
+                c = 'A' + rune(rookY)
+            }
+            if player == PlayerBlack {
+                c = toLower(c)
+            }
+            rights += string(c)
+        })
+    }
+
+    if rights == "" {
+        return "-"
+    }
+    return rights
+}
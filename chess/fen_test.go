@@ -0,0 +1,133 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestParseFEN(t *testing.T) {
+    board, toMove, err := ParseFEN("r3k2r/8/8/8/8/8/8/R3K1NR b Kq - 0 1")
+    require.NoError(t, err)
+    require.Equal(t, PlayerBlack, toMove)
+    require.Equal(t, NewPiece(PieceRook, PlayerBlack), board.GetPiece(0, 0))
+    require.Equal(t, NewPiece(PieceKing, PlayerBlack), board.GetPiece(0, 4))
+    require.Equal(t, NewPiece(PieceKnight, PlayerWhite), board.GetPiece(7, 6))
+    require.Equal(t, NoPiece(), board.GetPiece(7, 5))
+
+    require.False(t, board.isActive(7, 7))
+    require.True(t, board.isActive(7, 0))
+    require.True(t, board.isActive(0, 7))
+    require.False(t, board.isActive(0, 0))
+
+    require.Equal(t, "r3k2r/8/8/8/8/8/8/R3K1NR b Kq - 0 1", board.FEN(toMove))
+}
+
+func TestFENRoundTrip(t *testing.T) {
+    board := NewChessBoard()
+    board.SetStartingPos()
+
+    parsed, toMove, err := ParseFEN(board.FEN(PlayerWhite))
+    require.NoError(t, err)
+    require.Equal(t, PlayerWhite, toMove)
+    require.Equal(t, board.pieces, parsed.pieces)
+    require.Equal(t, board.FEN(PlayerWhite), parsed.FEN(PlayerWhite))
+    require.Equal(t, StartingFEN, board.FEN(PlayerWhite))
+}
+
+func TestParseFENErrors(t *testing.T) {
+    for _, fen := range []string{
+        "",
+        "8/8/8/8/8/8/8/8",
+        "8/8/8/8/8/8/8 w - - 0 1",
+        "9/8/8/8/8/8/8/8 w - - 0 1",
+        "ppppppppp/8/8/8/8/8/8/8 w - - 0 1",
+        "8/8/8/8/8/8/8/7x w - - 0 1",
+        "8/8/8/8/8/8/8/8 x - - 0 1",
+        "8/8/8/8/8/8/8/8 w Z - 0 1",
+    } {
+        _, _, err := ParseFEN(fen)
+        require.ErrorIs(t, err, InvalidFENError, fen)
+    }
+}
+
+func TestCheckmateAndStalemate(t *testing.T) {
+    board, _, err := ParseFEN("6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+    require.NoError(t, err)
+    require.False(t, board.IsCheckmate(PlayerBlack))
+
+    mated, err := board.MakeMove(NewMove(7, 0, 0, 0))
+    require.NoError(t, err)
+    require.True(t, mated.IsCheckmate(PlayerBlack))
+    require.False(t, mated.IsStalemate(PlayerBlack))
+
+    board, _, err = ParseFEN("7k/5Q2/6K1/8/8/8/8/8 b - - 0 1")
+    require.NoError(t, err)
+    require.True(t, board.IsStalemate(PlayerBlack))
+    require.False(t, board.IsCheckmate(PlayerBlack))
+}
+
+func TestMakeMove(t *testing.T) {
+    board, _, err := ParseFEN("k7/8/8/8/8/8/4P3/K7 w - - 0 1")
+    require.NoError(t, err)
+
+    nb, err := board.MakeMove(NewMove(6, 4, 4, 4))
+    require.NoError(t, err)
+    require.Equal(t, NewPiece(PiecePawn, PlayerWhite), nb.GetPiece(4, 4))
+    require.Equal(t, NoPiece(), board.GetPiece(4, 4))
+
+    _, err = board.MakeMove(NewMove(6, 4, 3, 4))
+    require.ErrorIs(t, err, IllegalMoveError)
+    _, err = board.MakeMove(NewMove(5, 4, 4, 4))
+    require.ErrorIs(t, err, EmptySquareSelectedError)
+}
+
+func TestCastleMove(t *testing.T) {
+    board, _, err := ParseFEN("4k3/8/8/8/8/8/8/R3K2R w KQ - 0 1")
+    require.NoError(t, err)
+
+    nb, err := board.MakeMove(NewMove(7, 4, 7, 6))
+    require.NoError(t, err)
+    require.Equal(t, NewPiece(PieceKing, PlayerWhite), nb.GetPiece(7, 6))
+    require.Equal(t, NewPiece(PieceRook, PlayerWhite), nb.GetPiece(7, 5))
+    require.Equal(t, NoPiece(), nb.GetPiece(7, 7))
+
+    nb, err = board.MakeMove(NewMove(7, 4, 7, 2))
+    require.NoError(t, err)
+    require.Equal(t, NewPiece(PieceKing, PlayerWhite), nb.GetPiece(7, 2))
+    require.Equal(t, NewPiece(PieceRook, PlayerWhite), nb.GetPiece(7, 3))
+    require.Equal(t, NoPiece(), nb.GetPiece(7, 0))
+
+    board, _, err = ParseFEN("4k3/8/8/8/8/8/8/R3K2R w Q - 0 1")
+    require.NoError(t, err)
+    _, err = board.MakeMove(NewMove(7, 4, 7, 6))
+    require.ErrorIs(t, err, IllegalMoveError)
+
+    // only a king still on its first rank may castle
+    board, _, err = ParseFEN("7k/8/8/8/8/8/RK6/8 w - - 0 1")
+    require.NoError(t, err)
+    require.NotContains(t, board.LegalMoves(PlayerWhite), NewMove(6, 1, 6, -1))
+
+    // no castling out of check
+    board, _, err = ParseFEN("4r1k1/8/8/8/8/8/8/R3K2R w KQ - 0 1")
+    require.NoError(t, err)
+    require.NotContains(t, board.LegalMoves(PlayerWhite), NewMove(7, 4, 7, 6))
+    require.NotContains(t, board.LegalMoves(PlayerWhite), NewMove(7, 4, 7, 2))
+
+    // the rook may pass an attacked square, only the king may not
+    board, _, err = ParseFEN("1r4k1/8/8/8/8/8/8/R3K2R w KQ - 0 1")
+    require.NoError(t, err)
+    require.False(t, board.Chess960())
+    require.Contains(t, board.LegalMoves(PlayerWhite), NewMove(7, 4, 7, 2))
+}
+
+func TestFENRun(t *testing.T) {
+    for s, want := range map[string][2]int{
+        "R":    {0, 0},
+        "3R":   {3, 1},
+        "10R":  {10, 2},
+        "12":   {12, 2},
+    } {
+        n, size := fenRun(s)
+        require.Equal(t, want, [2]int{n, size}, s)
+    }
+}
@@ -0,0 +1,106 @@
+package chess
+
+import "fmt"
+
+var GameOverError = fmt.Errorf("Game is over")
+
+// Game is a game played by the rules of a variant: every position reached
+// and the moves played between them.
+type Game struct {
+    variant   Variant
+    positions []*Board
+    moves     []Move
+    toMove    PlayerType
+}
+
+// NewGame starts a game of v from its starting position.
+func NewGame(v Variant) (*Game, error) {
+    board := newBoard(v.Dimensions())
+    board.SetVariant(v)
+    if err := v.SetStartingPos(board); err != nil {
+        return nil, err
+    }
+
+    return &Game{variant: v, positions: []*Board{board}, toMove: PlayerWhite}, nil
+}
+
+// NewGameFromFEN starts a game of v from a position in FEN.
+func NewGameFromFEN(v Variant, fen string) (*Game, error) {
+    return newGameFromFEN(v, fen, false)
+}
+
+// NewChess960GameFromFEN starts a game of v with Chess960 castling from a
+// position in FEN, read as ParseChess960FEN does.
+func NewChess960GameFromFEN(v Variant, fen string) (*Game, error) {
+    return newGameFromFEN(v, fen, true)
+}
+
+func newGameFromFEN(v Variant, fen string, chess960 bool) (*Game, error) {
+    board, toMove, err := parseFEN(fen, v, chess960)
+    if err != nil {
+        return nil, err
+    }
+
+    return &Game{variant: v, positions: []*Board{board}, toMove: toMove}, nil
+}
+
+func (g *Game) Variant() Variant {
+    return g.variant
+}
+
+// Board returns the current position.
+func (g *Game) Board() *Board {
+    return g.positions[len(g.positions)-1]
+}
+
+func (g *Game) ToMove() PlayerType {
+    return g.toMove
+}
+
+func (g *Game) Moves() []Move {
+    return g.moves
+}
+
+// Positions returns every position of the game, starting with the first.
+func (g *Game) Positions() []*Board {
+    return g.positions
+}
+
+func (g *Game) LegalMoves() []Move {
+    if _, over := g.Outcome(); over {
+        return nil
+    }
+    return g.Board().LegalMoves(g.toMove)
+}
+
+// Play plays m for the player to move.
+func (g *Game) Play(m Move) error {
+    if _, over := g.Outcome(); over {
+        return GameOverError
+    }
+
+    board := g.Board()
+    p := board.GetPiece(m.from.x, m.from.y)
+    if m.IsDrop() {
+        p = m.drop
+    }
+    if p.isPiece() && p.player != g.toMove {
+        return fmt.Errorf("%w: %v is not a move of %v", IllegalMoveError, m, g.toMove)
+    }
+    m = board.completeMove(m)
+    next, err := board.MakeMove(m)
+    if err != nil {
+        return err
+    }
+
+    g.positions = append(g.positions, next)
+    g.moves = append(g.moves, m)
+    g.toMove = Opponent(g.toMove)
+
+    return nil
+}
+
+// Outcome reports whether the game is over, and how it ended.
+func (g *Game) Outcome() (Outcome, bool) {
+    return g.variant.Outcome(g.Board(), g.toMove)
+}
@@ -0,0 +1,56 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func playSAN(t *testing.T, g *Game, sans ...string) {
+    for _, san := range sans {
+        m, err := g.Board().ParseSAN(san, g.ToMove())
+        require.NoError(t, err, san)
+        require.NoError(t, g.Play(m), san)
+    }
+}
+
+// gameFromFEN starts a game of v from fen, which must be a valid position.
+func gameFromFEN(t *testing.T, v Variant, fen string) *Game {
+    g, err := NewGameFromFEN(v, fen)
+    require.NoError(t, err)
+    return g
+}
+
+func TestGame(t *testing.T) {
+    g, err := NewGame(Standard{})
+    require.NoError(t, err)
+    require.Equal(t, "Standard", g.Variant().Name())
+    require.Equal(t, StartingFEN, g.Board().FEN(g.ToMove()))
+    require.Len(t, g.LegalMoves(), 20)
+
+    err = g.Play(NewMove(1, 4, 3, 4))
+    require.ErrorIs(t, err, IllegalMoveError, "black can't move first")
+
+    playSAN(t, g, "f3", "e5", "g4", "Qh4#")
+    require.Len(t, g.Moves(), 4)
+    require.Len(t, g.Positions(), 5)
+    require.Equal(t, PlayerWhite, g.ToMove())
+
+    outcome, over := g.Outcome()
+    require.True(t, over)
+    require.Equal(t, Outcome{Winner: PlayerBlack, Reason: ReasonCheckmate}, outcome)
+    require.Empty(t, g.LegalMoves())
+    require.ErrorIs(t, g.Play(NewMove(6, 0, 5, 0)), GameOverError)
+}
+
+func TestGameFromFEN(t *testing.T) {
+    g, err := NewGameFromFEN(Standard{}, "7k/8/4Q3/8/8/8/8/6K1 w - - 0 1")
+    require.NoError(t, err)
+    playSAN(t, g, "Qg6")
+
+    outcome, over := g.Outcome()
+    require.True(t, over)
+    require.Equal(t, Outcome{Winner: PlayerNone, Reason: ReasonStalemate}, outcome)
+
+    _, err = NewGameFromFEN(Standard{}, "8/8/8/8/8/8/8/8 w - - 0 1")
+    require.ErrorIs(t, err, InvalidPositionError)
+}
@@ -0,0 +1,57 @@
+package chess
+
+import "fmt"
+
+// HordeFEN is the starting position of Horde.
+const HordeFEN = "rnbqkbnr/pppppppp/8/1PP2PP1/PPPPPPPP/PPPPPPPP/PPPPPPPP/PPPPPPPP w kq - 0 1"
+
+// Horde is chess where white has 36 pawns and no king against the usual
+// army of black. White pawns on the first rank may move two squares like
+// those on the second. Black wins by taking every white piece, white by
+// mating the black king.
+type Horde struct {
+    Standard
+}
+
+func (Horde) Name() string {
+    return "Horde"
+}
+
+func (Horde) SetStartingPos(b *Board) error {
+    return b.setStartingFEN(HordeFEN)
+}
+
+// hordeWhiteRanks are the ranks white pawns move two squares from in Horde.
+var hordeWhiteRanks = []int{1, 2}
+
+func (v Horde) DoublePushRanks(player PlayerType) []int {
+    if player == PlayerWhite {
+        return hordeWhiteRanks
+    }
+    return v.Standard.DoublePushRanks(player)
+}
+
+// Validate checks the position as in standard chess, except that white has
+// no king and may have pawns on its first rank.
+func (Horde) Validate(b *Board, toMove PlayerType) error {
+    if err := b.validatePawns(PlayerWhite); err != nil {
+        return err
+    }
+    if err := b.validateKings(0, 1); err != nil {
+        return err
+    }
+
+    if b.InCheck(Opponent(toMove)) {
+        return fmt.Errorf("%w: %v is in check with %v to move", InvalidPositionError, Opponent(toMove), toMove)
+    }
+
+    return b.validateCastling()
+}
+
+func (v Horde) Outcome(b *Board, toMove PlayerType) (Outcome, bool) {
+    if b.pieceCount(PlayerWhite) == 0 {
+        return Outcome{Winner: PlayerBlack, Reason: ReasonNoPieces}, true
+    }
+
+    return v.Standard.Outcome(b, toMove)
+}
@@ -0,0 +1,36 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestHorde(t *testing.T) {
+    g, err := NewGame(Horde{})
+    require.NoError(t, err)
+    require.Equal(t, HordeFEN, g.Board().FEN(g.ToMove()))
+    require.Equal(t, 36, g.Board().pieceCount(PlayerWhite))
+
+    playSAN(t, g, "f6", "exf6")
+    require.Equal(t, 35, g.Board().pieceCount(PlayerWhite))
+
+    // first rank pawns move two squares once the way is clear
+    g = gameFromFEN(t, Horde{}, "4k3/8/8/8/8/8/8/P7 w - - 0 1")
+    require.ElementsMatch(t, []Move{NewMove(7, 0, 6, 0), NewMove(7, 0, 5, 0)}, g.LegalMoves())
+
+    _, err = NewGameFromFEN(Horde{}, "4k3/8/8/8/8/8/8/4K3 w - - 0 1")
+    require.ErrorIs(t, err, InvalidPositionError)
+}
+
+func TestHordeOutcome(t *testing.T) {
+    g := gameFromFEN(t, Horde{}, "4k3/8/8/8/8/8/3p4/4P3 b - - 0 1")
+    playSAN(t, g, "dxe1=Q")
+    outcome, over := g.Outcome()
+    require.True(t, over)
+    require.Equal(t, Outcome{Winner: PlayerBlack, Reason: ReasonNoPieces}, outcome)
+
+    g = gameFromFEN(t, Horde{}, "7k/5Q2/7P/8/8/8/8/8 w - - 0 1")
+    playSAN(t, g, "Qg7#")
+    outcome, _ = g.Outcome()
+    require.Equal(t, Outcome{Winner: PlayerWhite, Reason: ReasonCheckmate}, outcome)
+}
@@ -0,0 +1,35 @@
+package chess
+
+const ReasonKingOfTheHill EndReason = "King of the hill"
+
+// KingOfTheHill is standard chess where bringing the king to one of the four
+// centre squares also wins.
+type KingOfTheHill struct {
+    Standard
+}
+
+func (KingOfTheHill) Name() string {
+    return "King of the Hill"
+}
+
+func (v KingOfTheHill) Outcome(b *Board, toMove PlayerType) (Outcome, bool) {
+    for _, player := range []PlayerType{Opponent(toMove), toMove} {
+        if onHill(b, player) {
+            return Outcome{Winner: player, Reason: ReasonKingOfTheHill}, true
+        }
+    }
+
+    return v.Standard.Outcome(b, toMove)
+}
+
+// onHill reports whether the king of player stands on d4, e4, d5 or e5.
+func onHill(b *Board, player PlayerType) bool {
+    for x := b.height/2 - 1; x <= b.height/2; x++ {
+        for y := b.width/2 - 1; y <= b.width/2; y++ {
+            if b.isPieceOf(sqr(x, y), PieceKing, player) {
+                return true
+            }
+        }
+    }
+    return false
+}
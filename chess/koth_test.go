@@ -0,0 +1,34 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestKingOfTheHill(t *testing.T) {
+    g, err := NewGame(KingOfTheHill{})
+    require.NoError(t, err)
+    require.Equal(t, "King of the Hill", g.Variant().Name())
+    playSAN(t, g, "e3", "e6", "Ke2", "Ke7", "Kd3", "Kd6")
+    _, over := g.Outcome()
+    require.False(t, over)
+
+    playSAN(t, g, "Kd4")
+    outcome, over := g.Outcome()
+    require.True(t, over)
+    require.Equal(t, Outcome{Winner: PlayerWhite, Reason: ReasonKingOfTheHill}, outcome)
+    require.Empty(t, g.LegalMoves())
+
+    g, err = NewGameFromFEN(KingOfTheHill{}, "8/8/8/4k3/8/8/8/4K3 w - - 0 1")
+    require.NoError(t, err)
+    outcome, over = g.Outcome()
+    require.True(t, over)
+    require.Equal(t, PlayerBlack, outcome.Winner)
+
+    // checkmate still wins
+    g, err = NewGameFromFEN(KingOfTheHill{}, "7k/8/6K1/8/8/8/8/1Q6 w - - 0 1")
+    require.NoError(t, err)
+    playSAN(t, g, "Qb8#")
+    outcome, _ = g.Outcome()
+    require.Equal(t, Outcome{Winner: PlayerWhite, Reason: ReasonCheckmate}, outcome)
+}
@@ -0,0 +1,82 @@
+package chess
+
+import "fmt"
+
+// LosAlamosFEN is the starting position of Los Alamos chess.
+const LosAlamosFEN = "rnqknr/pppppp/6/6/PPPPPP/RNQKNR w - - 0 1"
+
+// GardnerFEN is the starting position of Gardner minichess.
+const GardnerFEN = "rnbqk/ppppp/5/PPPPP/RNBQK w - - 0 1"
+
+// LosAlamos is chess on a 6x6 board without bishops. Pawns only move one
+// square and there is no castling.
+type LosAlamos struct {
+    Standard
+}
+
+func (LosAlamos) Name() string {
+    return "Los Alamos"
+}
+
+func (LosAlamos) Dimensions() (width, height int) {
+    return 6, 6
+}
+
+func (LosAlamos) SetStartingPos(b *Board) error {
+    return setMinichessPos(b, LosAlamosFEN)
+}
+
+func (LosAlamos) DoublePushRanks(player PlayerType) []int {
+    return nil
+}
+
+func (LosAlamos) Validate(b *Board, toMove PlayerType) error {
+    return validateMinichess(b, toMove)
+}
+
+// Gardner is chess on a 5x5 board with one of every piece on the first
+// rank. Pawns only move one square and there is no castling.
+type Gardner struct {
+    Standard
+}
+
+func (Gardner) Name() string {
+    return "Gardner"
+}
+
+func (Gardner) Dimensions() (width, height int) {
+    return 5, 5
+}
+
+func (Gardner) SetStartingPos(b *Board) error {
+    return setMinichessPos(b, GardnerFEN)
+}
+
+func (Gardner) DoublePushRanks(player PlayerType) []int {
+    return nil
+}
+
+func (Gardner) Validate(b *Board, toMove PlayerType) error {
+    return validateMinichess(b, toMove)
+}
+
+// setMinichessPos sets up the position of fen with no castling rights.
+func setMinichessPos(b *Board, fen string) error {
+    if err := b.setStartingFEN(fen); err != nil {
+        return err
+    }
+    return b.SetCastlingRights("-")
+}
+
+// validateMinichess checks the position as in standard chess, and that no
+// one may castle.
+func validateMinichess(b *Board, toMove PlayerType) error {
+    if err := b.validate(toMove); err != nil {
+        return err
+    }
+    if rights := b.CastlingRights(); rights != "-" {
+        return fmt.Errorf("%w: castling rights %q in %v", InvalidPositionError, rights, b.Variant().Name())
+    }
+
+    return nil
+}
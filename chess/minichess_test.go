@@ -0,0 +1,34 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestLosAlamos(t *testing.T) {
+    g, err := NewGame(LosAlamos{})
+    require.NoError(t, err)
+    require.Equal(t, LosAlamosFEN, g.Board().FEN(g.ToMove()))
+    // pawns only move one square: 6 pawn moves and 2 moves for each knight
+    require.Len(t, g.LegalMoves(), 10)
+
+    playSAN(t, g, "c3", "d4", "cxd4")
+    require.Equal(t, "rnqknr/ppp1pp/3P2/6/PP1PPP/RNQKNR b - - 0 1", g.Board().FEN(g.ToMove()))
+
+    _, err = NewGameFromFEN(LosAlamos{}, "r2k1r/6/6/6/6/R2K1R w KQ - 0 1")
+    require.ErrorIs(t, err, InvalidPositionError)
+}
+
+func TestGardner(t *testing.T) {
+    g, err := NewGame(Gardner{})
+    require.NoError(t, err)
+    require.Equal(t, GardnerFEN, g.Board().FEN(g.ToMove()))
+    require.Len(t, g.LegalMoves(), 7)
+    require.Equal(t, "a2a3", g.Board().UCI(NewMove(3, 0, 2, 0)))
+
+    // pawns promote on the fifth rank
+    g, err = NewGameFromFEN(Gardner{}, "4k/P4/5/5/K4 w - - 0 1")
+    require.NoError(t, err)
+    playSAN(t, g, "a5=Q+")
+    require.Equal(t, NewPiece(PieceQueen, PlayerWhite), g.Board().GetPiece(0, 0))
+}
@@ -0,0 +1,98 @@
+package chess
+
+import "fmt"
+
+// Move is a piece moving from one square to another, or, in variants with
+// drops, a piece from the pocket put on an empty square. A pawn move to the
+// last rank names the piece the pawn promotes to. Moves listed by the board
+// always do; a move made with NewMove promotes to a queen.
+type Move struct {
+    from      square
+    to        square
+    drop      Piece
+    promotion PieceType
+}
+
+func NewMove(fromX, fromY, toX, toY int) Move {
+    return Move{from: sqr(fromX, fromY), to: sqr(toX, toY)}
+}
+
+// NewDrop returns the move dropping piece from its owner's pocket on (x, y).
+func NewDrop(piece Piece, x, y int) Move {
+    return Move{from: sqr(x, y), to: sqr(x, y), drop: piece}
+}
+
+// promotionPieces are the pieces a pawn may promote to, the queen first.
+// Antichess adds the king.
+var promotionPieces = []PieceType{PieceQueen, PieceRook, PieceBishop, PieceKnight}
+
+// NewPromotion returns the move of a pawn promoting to a piece of type t.
+func NewPromotion(fromX, fromY, toX, toY int, t PieceType) Move {
+    return Move{from: sqr(fromX, fromY), to: sqr(toX, toY), promotion: t}
+}
+
+// Promotion returns the piece type a pawn promotes to if the move names
+// one, or PieceNone.
+func (m Move) Promotion() PieceType {
+    if m.promotion == "" {
+        return PieceNone
+    }
+    return m.promotion
+}
+
+func (m Move) IsDrop() bool {
+    return m.drop.pieceType != "" && m.drop.isPiece()
+}
+
+// Drop returns the piece a drop puts on the board.
+func (m Move) Drop() Piece {
+    return m.drop
+}
+
+func (m Move) From() square {
+    return m.from
+}
+
+func (m Move) To() square {
+    return m.to
+}
+
+// String writes m in UCI notation as on a standard board. Board.UCI writes
+// moves on boards of other sizes.
+func (m Move) String() string {
+    return m.uci(BoardSize)
+}
+
+// uci writes m in UCI notation on a board of height ranks.
+func (m Move) uci(height int) string {
+    if m.IsDrop() {
+        return fmt.Sprintf("%c@%v", fenLetter(NewPiece(m.drop.pieceType, PlayerWhite)), m.to.name(height))
+    }
+    if m.promotion != "" {
+        return fmt.Sprintf("%v%v%c", m.from.name(height), m.to.name(height), fenLetter(NewPiece(m.promotion, PlayerBlack)))
+    }
+    return m.from.name(height) + m.to.name(height)
+}
+
+// LegalMoves returns every legal move of player, castling included, in the
+// order the pieces are found on the board. The variant of the board has the
+// final say.
+func (b *Board) LegalMoves(player PlayerType) []Move {
+    moves := make([]Move, 0, 40)
+    for i := 0; i < b.height; i++ {
+        for j := 0; j < b.width; j++ {
+            p := b.GetPiece(i, j)
+            if !p.isPiece() || p.player != player {
+                continue
+            }
+            sel, err := b.selectLegal(i, j)
+            if err != nil {
+                continue
+            }
+            moves = append(moves, sel.moves()...)
+        }
+    }
+    moves = append(moves, b.Variant().ExtraMoves(b, player)...)
+
+    return b.Variant().FilterMoves(b, player, moves)
+}
@@ -0,0 +1,26 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestMoveString(t *testing.T) {
+    require.Equal(t, "e2e4", NewMove(6, 4, 4, 4).String())
+    require.Equal(t, "a8h1", NewMove(0, 0, 7, 7).String())
+}
+
+func TestLegalMoves(t *testing.T) {
+    board := NewChessBoard()
+    board.SetStartingPos()
+    require.Len(t, board.LegalMoves(PlayerWhite), 20)
+    require.Len(t, board.LegalMoves(PlayerBlack), 20)
+
+    board = NewChessBoard()
+    board.SetPiece(7, 0, NewPiece(PieceKing, PlayerWhite))
+    board.SetPiece(6, 1, NewPiece(PieceQueen, PlayerBlack))
+    board.SetPiece(3, 7, NewPiece(PieceRook, PlayerWhite))
+    moves := board.LegalMoves(PlayerWhite)
+    // the king can only take the queen, the rook has nothing to block
+    require.ElementsMatch(t, []Move{NewMove(7, 0, 6, 1)}, moves)
+}
@@ -13,6 +13,10 @@ const (
     PieceRook   PieceType = "Rook"
     PieceQueen  PieceType = "Queen"
     PieceKing   PieceType = "King"
+    // the pieces of Capablanca chess, moving as a bishop or a knight and as
+    // a rook or a knight
+    PieceArchbishop PieceType = "Archbishop"
+    PieceChancellor PieceType = "Chancellor"
 )
 
 const (
@@ -21,6 +25,20 @@ const (
     PlayerBlack PlayerType = "Black"
 )
 
+// Material values in centipawns. The king is given a value larger than all
+// other material so that losing it always outweighs any gain.
+const (
+    PawnValue   = 100
+    KnightValue = 320
+    BishopValue = 330
+    RookValue   = 500
+    QueenValue  = 900
+    KingValue   = 20000
+
+    ArchbishopValue = 875
+    ChancellorValue = 925
+)
+
 type Piece struct {
     pieceType PieceType
     player PlayerType
@@ -60,3 +78,26 @@ func (p Piece) Type() PieceType {
 func (p Piece) Player() PlayerType {
     return p.player
 }
+
+func (p Piece) Value() int {
+    switch t := p.pieceType; t {
+    case PiecePawn:
+        return PawnValue
+    case PieceKnight:
+        return KnightValue
+    case PieceBishop:
+        return BishopValue
+    case PieceRook:
+        return RookValue
+    case PieceQueen:
+        return QueenValue
+    case PieceKing:
+        return KingValue
+    case PieceArchbishop:
+        return ArchbishopValue
+    case PieceChancellor:
+        return ChancellorValue
+    default:
+        return 0
+    }
+}
@@ -0,0 +1,100 @@
+package chess
+
+// pin is a piece standing alone between a king and an enemy slider. The ray
+// holds the squares from the king (exclusive) up to the slider (inclusive).
+type pin struct {
+    piece square
+    ray   []square
+}
+
+// Pinned returns the pieces of player that are pinned to their own king.
+func (b *Board) Pinned(player PlayerType) []square {
+    pins := b.pins(player, player)
+    pinned := make([]square, 0, len(pins))
+    for _, p := range pins {
+        pinned = append(pinned, p.piece)
+    }
+
+    return pinned
+}
+
+// PinRay returns the squares the piece on (x, y) can move along while
+// staying pinned, including the capture of the pinning piece. It returns
+// nil when the piece is not pinned.
+func (b *Board) PinRay(x, y int) []square {
+    player := b.GetPiece(x, y).player
+    for _, p := range b.pins(player, player) {
+        if p.piece.comp(x, y) {
+            return p.ray
+        }
+    }
+
+    return nil
+}
+
+// DiscoveredCheckCandidates returns the pieces of player that would uncover
+// a check on the opponent's king by moving off the line they block.
+func (b *Board) DiscoveredCheckCandidates(player PlayerType) []square {
+    pins := b.pins(Opponent(player), player)
+    candidates := make([]square, 0, len(pins))
+    for _, p := range pins {
+        candidates = append(candidates, p.piece)
+    }
+
+    return candidates
+}
+
+// pins looks for pieces of blocker standing alone between a king of
+// kingPlayer and a slider of the kingPlayer's opponent.
+func (b *Board) pins(kingPlayer, blocker PlayerType) []pin {
+    pins := make([]pin, 0)
+    for i := 0; i < b.height; i++ {
+        for j := 0; j < b.width; j++ {
+            if !b.isPieceOf(sqr(i, j), PieceKing, kingPlayer) {
+                continue
+            }
+            for _, d := range rookDirs {
+                if p, ok := b.pinInDir(i, j, d, blocker, Opponent(kingPlayer), movesLikeRook); ok {
+                    pins = append(pins, p)
+                }
+            }
+            for _, d := range bishopDirs {
+                if p, ok := b.pinInDir(i, j, d, blocker, Opponent(kingPlayer), movesLikeBishop); ok {
+                    pins = append(pins, p)
+                }
+            }
+        }
+    }
+
+    return pins
+}
+
+func (b *Board) pinInDir(x, y int, dir square, blocker, attacker PlayerType, slides func(PieceType) bool) (pin, bool) {
+    ray := make([]square, 0, max(b.width, b.height))
+    blocking := sqr(-1, -1)
+    for i := 1; i < max(b.width, b.height); i++ {
+        sq := sqr(x+i*dir.x, y+i*dir.y)
+        if !b.inBounds(sq) {
+            break
+        }
+        ray = append(ray, sq)
+
+        p := b.GetPiece(sq.x, sq.y)
+        if !p.isPiece() {
+            continue
+        }
+        if !b.inBounds(blocking) {
+            if p.player != blocker {
+                break
+            }
+            blocking = sq
+            continue
+        }
+        if p.player == attacker && slides(p.pieceType) {
+            return pin{piece: blocking, ray: ray}, true
+        }
+        break
+    }
+
+    return pin{}, false
+}
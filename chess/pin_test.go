@@ -0,0 +1,106 @@
+package chess
+
+import (
+    "math/rand"
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestPinned(t *testing.T) {
+    board := NewChessBoard()
+    board.SetPiece(7, 4, NewPiece(PieceKing, PlayerWhite))
+    board.SetPiece(5, 4, NewPiece(PieceKnight, PlayerWhite))
+    board.SetPiece(2, 4, NewPiece(PieceRook, PlayerBlack))
+    board.SetPiece(6, 3, NewPiece(PieceBishop, PlayerWhite))
+    board.SetPiece(4, 1, NewPiece(PieceQueen, PlayerBlack))
+    board.SetPiece(6, 5, NewPiece(PiecePawn, PlayerWhite))
+    board.SetPiece(5, 6, NewPiece(PiecePawn, PlayerWhite))
+    board.SetPiece(4, 7, NewPiece(PieceBishop, PlayerBlack))
+
+    pinned := board.Pinned(PlayerWhite)
+    require.Len(t, pinned, 2)
+    require.Contains(t, pinned, sqr(5, 4))
+    require.Contains(t, pinned, sqr(6, 3))
+
+    require.Equal(t, []square{sqr(6, 4), sqr(5, 4), sqr(4, 4), sqr(3, 4), sqr(2, 4)}, board.PinRay(5, 4))
+    require.Equal(t, []square{sqr(6, 3), sqr(5, 2), sqr(4, 1)}, board.PinRay(6, 3))
+    require.Nil(t, board.PinRay(6, 5))
+    require.Empty(t, board.Pinned(PlayerBlack))
+}
+
+func TestPinnedPieceMoves(t *testing.T) {
+    board := NewChessBoard()
+    board.SetPiece(7, 4, NewPiece(PieceKing, PlayerWhite))
+    board.SetPiece(5, 4, NewPiece(PieceRook, PlayerWhite))
+    board.SetPiece(2, 4, NewPiece(PieceRook, PlayerBlack))
+
+    sel, err := board.SelectPiece(5, 4)
+    require.NoError(t, err)
+    require.ElementsMatch(t, []square{sqr(6, 4), sqr(4, 4), sqr(3, 4), sqr(2, 4)}, sel.possibleMoves)
+    require.Equal(t, []square{sqr(2, 4)}, sel.threatenPieces)
+
+    board.SetPiece(5, 4, NewPiece(PieceKnight, PlayerWhite))
+    sel, err = board.SelectPiece(5, 4)
+    require.NoError(t, err)
+    require.Empty(t, sel.possibleMoves)
+}
+
+func TestDiscoveredCheckCandidates(t *testing.T) {
+    board := NewChessBoard()
+    board.SetPiece(0, 4, NewPiece(PieceKing, PlayerBlack))
+    board.SetPiece(3, 4, NewPiece(PieceKnight, PlayerWhite))
+    board.SetPiece(7, 4, NewPiece(PieceRook, PlayerWhite))
+    board.SetPiece(2, 2, NewPiece(PiecePawn, PlayerWhite))
+    board.SetPiece(4, 0, NewPiece(PieceBishop, PlayerWhite))
+    board.SetPiece(1, 5, NewPiece(PiecePawn, PlayerBlack))
+    board.SetPiece(3, 7, NewPiece(PieceBishop, PlayerWhite))
+
+    require.ElementsMatch(t, []square{sqr(3, 4), sqr(2, 2)}, board.DiscoveredCheckCandidates(PlayerWhite))
+    require.Equal(t, []square{sqr(1, 5)}, board.Pinned(PlayerBlack))
+}
+
+// TestPinAwareSelection compares the pin based filtering against trying
+// every move on random positions.
+func TestPinAwareSelection(t *testing.T) {
+    types := []PieceType{PiecePawn, PieceKnight, PieceBishop, PieceRook, PieceQueen}
+    r := rand.New(rand.NewSource(1))
+
+    for n := 0; n < 200; n++ {
+        board := NewChessBoard()
+        board.SetPiece(r.Intn(BoardSize), r.Intn(BoardSize), NewPiece(PieceKing, PlayerWhite))
+        for k := 0; k < 10; k++ {
+            x, y := 1+r.Intn(BoardSize-2), r.Intn(BoardSize)
+            if board.hasPiece(x, y) {
+                continue
+            }
+            player := PlayerWhite
+            if k%2 == 0 {
+                player = PlayerBlack
+            }
+            board.SetPiece(x, y, NewPiece(types[r.Intn(len(types))], player))
+        }
+
+        for i := 0; i < BoardSize; i++ {
+            for j := 0; j < BoardSize; j++ {
+                p := board.GetPiece(i, j)
+                if p.player != PlayerWhite || p.pieceType == PieceKing {
+                    continue
+                }
+                sel, err := board.SelectPiece(i, j)
+                require.NoError(t, err)
+
+                ref, err := board.SelectPieceIgnoreCheck(i, j)
+                require.NoError(t, err)
+                expected := make([]square, 0)
+                for _, m := range ref.possibleMoves {
+                    nb, err := board.repositionPiece(i, j, m.x, m.y)
+                    require.NoError(t, err)
+                    if !nb.InCheck(PlayerWhite) {
+                        expected = append(expected, m)
+                    }
+                }
+                require.ElementsMatch(t, expected, sel.possibleMoves)
+            }
+        }
+    }
+}
@@ -0,0 +1,81 @@
+package chess
+
+import "fmt"
+
+const ReasonRaceFinished EndReason = "King reached the eighth rank"
+
+// RacingKingsFEN is the starting position of Racing Kings.
+const RacingKingsFEN = "8/8/8/8/8/8/krbnNBRK/qrbnNBRQ w - - 0 1"
+
+// RacingKings is a race of the kings to the eighth rank, from a position
+// where both sides start on the first two ranks. No move may give check or
+// leave the own king in check. The first king to reach the eighth rank
+// wins, but if white gets there first black has one move to draw by
+// reaching it as well.
+type RacingKings struct {
+    Standard
+}
+
+func (RacingKings) Name() string {
+    return "Racing Kings"
+}
+
+func (RacingKings) SetStartingPos(b *Board) error {
+    return b.setStartingFEN(RacingKingsFEN)
+}
+
+// Validate checks the position as in standard chess, except that neither
+// king may be in check.
+func (RacingKings) Validate(b *Board, toMove PlayerType) error {
+    if err := b.validate(toMove); err != nil {
+        return err
+    }
+    if b.InCheck(toMove) {
+        return fmt.Errorf("%w: %v is in check", InvalidPositionError, toMove)
+    }
+
+    return nil
+}
+
+func (RacingKings) Legal(before, after *Board, m Move, player PlayerType) bool {
+    return !after.InCheck(player) && !after.InCheck(Opponent(player))
+}
+
+func (RacingKings) Outcome(b *Board, toMove PlayerType) (Outcome, bool) {
+    white, black := b.kingOnGoal(PlayerWhite), b.kingOnGoal(PlayerBlack)
+    switch {
+    case white && black:
+        return Outcome{Winner: PlayerNone, Reason: ReasonRaceFinished}, true
+    case black:
+        return Outcome{Winner: PlayerBlack, Reason: ReasonRaceFinished}, true
+    case white && (toMove == PlayerWhite || !b.canReachGoal(PlayerBlack)):
+        return Outcome{Winner: PlayerWhite, Reason: ReasonRaceFinished}, true
+    case white:
+        return Outcome{}, false
+    }
+
+    if len(b.LegalMoves(toMove)) == 0 {
+        return Outcome{Winner: PlayerNone, Reason: ReasonStalemate}, true
+    }
+    return Outcome{}, false
+}
+
+// kingOnGoal reports whether the king of player stands on the eighth rank.
+func (b *Board) kingOnGoal(player PlayerType) bool {
+    for j := 0; j < b.width; j++ {
+        if b.isPieceOf(sqr(0, j), PieceKing, player) {
+            return true
+        }
+    }
+    return false
+}
+
+// canReachGoal reports whether player can move its king to the eighth rank.
+func (b *Board) canReachGoal(player PlayerType) bool {
+    for _, m := range b.LegalMoves(player) {
+        if m.to.x == 0 && b.isPieceOf(m.from, PieceKing, player) {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,43 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestRacingKingsNoChecks(t *testing.T) {
+    g, err := NewGame(RacingKings{})
+    require.NoError(t, err)
+    require.Equal(t, RacingKingsFEN, g.Board().FEN(g.ToMove()))
+
+    // Ng3 is fine, but Nc3 would check the king on a2
+    require.Contains(t, g.LegalMoves(), NewMove(6, 4, 5, 6))
+    require.NotContains(t, g.LegalMoves(), NewMove(6, 4, 5, 2))
+
+    _, err = NewGameFromFEN(RacingKings{}, "8/8/8/8/8/8/k6R/7K b - - 0 1")
+    require.ErrorIs(t, err, InvalidPositionError)
+}
+
+func TestRacingKingsOutcome(t *testing.T) {
+    g := gameFromFEN(t, RacingKings{}, "8/k6K/8/8/8/8/8/8 b - - 0 1")
+    playSAN(t, g, "Ka8")
+    outcome, over := g.Outcome()
+    require.True(t, over)
+    require.Equal(t, Outcome{Winner: PlayerBlack, Reason: ReasonRaceFinished}, outcome)
+
+    // black gets one more move to catch up
+    g = gameFromFEN(t, RacingKings{}, "8/k6K/8/8/8/8/8/8 w - - 0 1")
+    playSAN(t, g, "Kh8")
+    _, over = g.Outcome()
+    require.False(t, over)
+    playSAN(t, g, "Kb8")
+    outcome, over = g.Outcome()
+    require.True(t, over)
+    require.Equal(t, Outcome{Winner: PlayerNone, Reason: ReasonRaceFinished}, outcome)
+
+    g = gameFromFEN(t, RacingKings{}, "8/7K/8/k7/8/8/8/8 w - - 0 1")
+    playSAN(t, g, "Kg8")
+    outcome, over = g.Outcome()
+    require.True(t, over)
+    require.Equal(t, Outcome{Winner: PlayerWhite, Reason: ReasonRaceFinished}, outcome)
+}
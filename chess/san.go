@@ -0,0 +1,157 @@
+package chess
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+var InvalidSANError = fmt.Errorf("Invalid SAN")
+
+var sanPieces = map[byte]PieceType{
+    'N': PieceKnight,
+    'B': PieceBishop,
+    'R': PieceRook,
+    'Q': PieceQueen,
+    'K': PieceKing,
+    'A': PieceArchbishop,
+    'C': PieceChancellor,
+}
+
+// ParseSAN finds the legal move of toMove written in Standard Algebraic
+// Notation. A promotion that names no piece is to a queen, and en passant
+// captures are not supported by the board. Drops are written as in
+// Crazyhouse, "N@f3", with "P" or no letter for pawns.
+func (b *Board) ParseSAN(san string, toMove PlayerType) (Move, error) {
+    s := strings.TrimRight(san, "+#!?")
+    if s == "" {
+        return Move{}, fmt.Errorf("%w: empty move", InvalidSANError)
+    }
+
+    switch strings.ReplaceAll(s, "0", "O") {
+    case "O-O":
+        return b.sanCastle(san, toMove, true)
+    case "O-O-O":
+        return b.sanCastle(san, toMove, false)
+    }
+
+    if i := strings.IndexByte(s, '@'); i >= 0 {
+        return b.sanDrop(san, s[:i], s[i+1:], toMove)
+    }
+
+    var promotion PieceType
+    if i := strings.IndexByte(s, '='); i >= 0 {
+        t, ok := sanPieces[s[len(s)-1]]
+        if !ok || i != len(s)-2 {
+            return Move{}, fmt.Errorf("%w: unsupported promotion in %q", InvalidSANError, san)
+        }
+        promotion = t
+        s = s[:i]
+    }
+
+    pieceType := PiecePawn
+    if t, ok := sanPieces[s[0]]; ok {
+        pieceType = t
+        s = s[1:]
+    }
+    s = strings.ReplaceAll(s, "x", "")
+    k := strings.LastIndexFunc(s, func(c rune) bool { return c >= 'a' && c <= 'z' })
+    if k < 0 {
+        return Move{}, fmt.Errorf("%w: no target square in %q", InvalidSANError, san)
+    }
+
+    to, ok := b.parseSquare(s[k:])
+    if !ok {
+        return Move{}, fmt.Errorf("%w: bad target square in %q", InvalidSANError, san)
+    }
+    hint := s[:k]
+
+    found := make([]Move, 0, 1)
+    for _, m := range b.LegalMoves(toMove) {
+        if m.to != to || m.promotion != promotion && (promotion != "" || m.promotion != PieceQueen) || b.GetPiece(m.from.x, m.from.y).pieceType != pieceType {
+            continue
+        }
+        if !b.matchesHint(m.from, hint) {
+            continue
+        }
+        if pieceType == PieceKing && b.IsCastle(m) {
+            continue
+        }
+        found = append(found, m)
+    }
+
+    if len(found) != 1 {
+        return Move{}, fmt.Errorf("%w: %q matches %d legal moves", InvalidSANError, san, len(found))
+    }
+
+    return found[0], nil
+}
+
+// matchesHint reports whether sq is on the file, the rank or the square
+// hint names.
+func (b *Board) matchesHint(sq square, hint string) bool {
+    file, rank := hint, ""
+    if i := strings.IndexAny(hint, "0123456789"); i >= 0 {
+        file, rank = hint[:i], hint[i:]
+    }
+    name := b.SquareName(sq)
+    return strings.HasPrefix(name, file) && (rank == "" || name[1:] == rank)
+}
+
+func (b *Board) sanCastle(san string, toMove PlayerType, right bool) (Move, error) {
+    for _, m := range b.LegalMoves(toMove) {
+        if b.isPieceOf(m.from, PieceKing, toMove) && b.IsCastle(m) && (m.to.y > m.from.y) == right {
+            return m, nil
+        }
+    }
+
+    return Move{}, fmt.Errorf("%w: %q is not legal", InvalidSANError, san)
+}
+
+// sanDrop finds a drop written as "N@f3", with no letter or "P" for pawns.
+func (b *Board) sanDrop(san, piece, target string, toMove PlayerType) (Move, error) {
+    pieceType := PiecePawn
+    if piece != "" && piece != "P" {
+        t, ok := sanPieces[piece[0]]
+        if !ok || len(piece) != 1 {
+            return Move{}, fmt.Errorf("%w: bad piece to drop in %q", InvalidSANError, san)
+        }
+        pieceType = t
+    }
+    to, ok := b.parseSquare(target)
+    if !ok {
+        return Move{}, fmt.Errorf("%w: bad target square in %q", InvalidSANError, san)
+    }
+
+    want := NewDrop(NewPiece(pieceType, toMove), to.x, to.y)
+    for _, m := range b.LegalMoves(toMove) {
+        if m == want {
+            return m, nil
+        }
+    }
+
+    return Move{}, fmt.Errorf("%w: %q is not legal", InvalidSANError, san)
+}
+
+func (b *Board) parseSquare(s string) (square, bool) {
+    if len(s) < 2 || s[0] < 'a' || s[1] < '1' || s[1] > '9' {
+        return square{}, false
+    }
+    rank, err := strconv.Atoi(s[1:])
+    if err != nil {
+        return square{}, false
+    }
+    sq := sqr(b.height-rank, int(s[0]-'a'))
+    return sq, b.inBounds(sq)
+}
+
+// SquareName names sq on the board, such as "e4".
+func (b *Board) SquareName(sq square) string {
+    return sq.name(b.height)
+}
+
+// UCI writes m in the notation of the UCI protocol, as Move.String does on
+// a standard board.
+func (b *Board) UCI(m Move) string {
+    return m.uci(b.height)
+}
@@ -0,0 +1,85 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestParseSAN(t *testing.T) {
+    board, _, err := ParseFEN(StartingFEN)
+    require.NoError(t, err)
+
+    m, err := board.ParseSAN("e4", PlayerWhite)
+    require.NoError(t, err)
+    require.Equal(t, NewMove(6, 4, 4, 4), m)
+
+    m, err = board.ParseSAN("Nf3", PlayerWhite)
+    require.NoError(t, err)
+    require.Equal(t, NewMove(7, 6, 5, 5), m)
+
+    m, err = board.ParseSAN("Nc6", PlayerBlack)
+    require.NoError(t, err)
+    require.Equal(t, NewMove(0, 1, 2, 2), m)
+
+    _, err = board.ParseSAN("e5", PlayerWhite)
+    require.ErrorIs(t, err, InvalidSANError)
+    _, err = board.ParseSAN("Qh5", PlayerWhite)
+    require.ErrorIs(t, err, InvalidSANError)
+}
+
+func TestParseSANDisambiguation(t *testing.T) {
+    board, _, err := ParseFEN("4k3/8/8/3p4/8/2N1N3/8/R3K2R w KQ - 0 1")
+    require.NoError(t, err)
+
+    _, err = board.ParseSAN("Nxd5", PlayerWhite)
+    require.ErrorIs(t, err, InvalidSANError)
+
+    m, err := board.ParseSAN("Ncxd5+", PlayerWhite)
+    require.NoError(t, err)
+    require.Equal(t, NewMove(5, 2, 3, 3), m)
+
+    m, err = board.ParseSAN("Rd1", PlayerWhite)
+    require.NoError(t, err)
+    require.Equal(t, NewMove(7, 0, 7, 3), m)
+
+    m, err = board.ParseSAN("O-O", PlayerWhite)
+    require.NoError(t, err)
+    require.Equal(t, NewMove(7, 4, 7, 6), m)
+
+    m, err = board.ParseSAN("O-O-O", PlayerWhite)
+    require.NoError(t, err)
+    require.Equal(t, NewMove(7, 4, 7, 2), m)
+
+    _, err = board.ParseSAN("O-O", PlayerBlack)
+    require.ErrorIs(t, err, InvalidSANError)
+}
+
+func TestParseSANPromotion(t *testing.T) {
+    board, _, err := ParseFEN("4k3/1P6/8/8/8/8/8/4K3 w - - 0 1")
+    require.NoError(t, err)
+
+    m, err := board.ParseSAN("b8=Q", PlayerWhite)
+    require.NoError(t, err)
+    require.Equal(t, NewPromotion(1, 1, 0, 1, PieceQueen), m)
+    require.Equal(t, "b7b8q", board.UCI(m))
+    require.Contains(t, board.LegalMoves(PlayerWhite), m)
+
+    // no piece named is a queen
+    m, err = board.ParseSAN("b8", PlayerWhite)
+    require.NoError(t, err)
+    require.Equal(t, NewPromotion(1, 1, 0, 1, PieceQueen), m)
+    nb, err := board.MakeMove(NewMove(1, 1, 0, 1))
+    require.NoError(t, err)
+    require.Equal(t, NewPiece(PieceQueen, PlayerWhite), nb.GetPiece(0, 1))
+
+    // underpromotions
+    m, err = board.ParseSAN("b8=N", PlayerWhite)
+    require.NoError(t, err)
+    require.Equal(t, "b7b8n", board.UCI(m))
+    nb, err = board.MakeMove(m)
+    require.NoError(t, err)
+    require.Equal(t, NewPiece(PieceKnight, PlayerWhite), nb.GetPiece(0, 1))
+
+    _, err = board.ParseSAN("b8=K", PlayerWhite)
+    require.ErrorIs(t, err, InvalidSANError)
+}
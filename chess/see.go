@@ -0,0 +1,56 @@
+package chess
+
+// SEE (static exchange evaluation) returns the material balance, from the
+// moving side's point of view, of playing m and then letting both sides
+// recapture on the target square with their least valuable attacker for as
+// long as it pays off. Sliders behind other attackers join in as the
+// pieces in front of them are exchanged.
+func (b *Board) SEE(m Move) int {
+    piece := b.GetPiece(m.from.x, m.from.y)
+    if !piece.isPiece() {
+        return 0
+    }
+
+    nb := b.copy()
+    gain := []int{nb.GetPiece(m.to.x, m.to.y).Value()}
+    onSquare := piece
+    nb.pieces[m.from.x][m.from.y] = NoPiece()
+    nb.pieces[m.to.x][m.to.y] = piece
+
+    side := Opponent(piece.player)
+    for {
+        sq, ok := nb.leastValuableAttacker(m.to.x, m.to.y, side)
+        if !ok {
+            break
+        }
+        attacker := nb.GetPiece(sq.x, sq.y)
+        nb.pieces[sq.x][sq.y] = NoPiece()
+        nb.pieces[m.to.x][m.to.y] = attacker
+        if attacker.pieceType == PieceKing && nb.IsAttacked(m.to.x, m.to.y, Opponent(side)) {
+            break
+        }
+
+        gain = append(gain, onSquare.Value() - gain[len(gain)-1])
+        onSquare = attacker
+        side = Opponent(side)
+    }
+
+    for d := len(gain) - 1; d > 0; d-- {
+        if -gain[d-1] < gain[d] {
+            gain[d-1] = -gain[d]
+        }
+    }
+
+    return gain[0]
+}
+
+func (b *Board) leastValuableAttacker(x, y int, player PlayerType) (square, bool) {
+    best := sqr(-1, -1)
+    for _, sq := range b.Attackers(x, y, player) {
+        if !b.inBounds(best) || b.GetPiece(sq.x, sq.y).Value() < b.GetPiece(best.x, best.y).Value() {
+            best = sq
+        }
+    }
+
+    return best, b.inBounds(best)
+}
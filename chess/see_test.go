@@ -0,0 +1,69 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestSEE(t *testing.T) {
+    t.Run("UndefendedPawn", func(t *testing.T) {
+        board := NewChessBoard()
+        board.SetPiece(7, 3, NewPiece(PieceRook, PlayerWhite))
+        board.SetPiece(3, 3, NewPiece(PiecePawn, PlayerBlack))
+        require.Equal(t, PawnValue, board.SEE(NewMove(7, 3, 3, 3)))
+    })
+    t.Run("QueenTakesDefendedPawn", func(t *testing.T) {
+        board := NewChessBoard()
+        board.SetPiece(7, 3, NewPiece(PieceQueen, PlayerWhite))
+        board.SetPiece(3, 3, NewPiece(PiecePawn, PlayerBlack))
+        board.SetPiece(2, 4, NewPiece(PiecePawn, PlayerBlack))
+        require.Equal(t, PawnValue-QueenValue, board.SEE(NewMove(7, 3, 3, 3)))
+    })
+    t.Run("PawnTakesDefendedKnight", func(t *testing.T) {
+        board := NewChessBoard()
+        board.SetPiece(4, 4, NewPiece(PiecePawn, PlayerWhite))
+        board.SetPiece(3, 3, NewPiece(PieceKnight, PlayerBlack))
+        board.SetPiece(2, 4, NewPiece(PiecePawn, PlayerBlack))
+        require.Equal(t, KnightValue-PawnValue, board.SEE(NewMove(4, 4, 3, 3)))
+    })
+    t.Run("RookXRay", func(t *testing.T) {
+        board := NewChessBoard()
+        board.SetPiece(6, 3, NewPiece(PieceRook, PlayerWhite))
+        board.SetPiece(3, 3, NewPiece(PiecePawn, PlayerBlack))
+        board.SetPiece(0, 3, NewPiece(PieceRook, PlayerBlack))
+        require.Equal(t, PawnValue-RookValue, board.SEE(NewMove(6, 3, 3, 3)))
+
+        board.SetPiece(7, 3, NewPiece(PieceRook, PlayerWhite))
+        require.Equal(t, PawnValue, board.SEE(NewMove(6, 3, 3, 3)))
+    })
+    t.Run("KingCannotRecaptureDefended", func(t *testing.T) {
+        board := NewChessBoard()
+        board.SetPiece(7, 3, NewPiece(PieceRook, PlayerWhite))
+        board.SetPiece(3, 3, NewPiece(PiecePawn, PlayerBlack))
+        board.SetPiece(2, 3, NewPiece(PieceKing, PlayerBlack))
+        require.Equal(t, PawnValue-RookValue, board.SEE(NewMove(7, 3, 3, 3)))
+
+        board.SetPiece(5, 1, NewPiece(PieceBishop, PlayerWhite))
+        require.Equal(t, PawnValue, board.SEE(NewMove(7, 3, 3, 3)))
+    })
+    t.Run("QuietMove", func(t *testing.T) {
+        board := NewChessBoard()
+        board.SetPiece(7, 3, NewPiece(PieceQueen, PlayerWhite))
+        board.SetPiece(2, 4, NewPiece(PiecePawn, PlayerBlack))
+        require.Equal(t, -QueenValue, board.SEE(NewMove(7, 3, 3, 3)))
+        require.Equal(t, 0, board.SEE(NewMove(7, 3, 4, 3)))
+    })
+}
+
+func TestLosingCaptures(t *testing.T) {
+    board := NewChessBoard()
+    board.SetPiece(4, 3, NewPiece(PieceQueen, PlayerWhite))
+    board.SetPiece(2, 3, NewPiece(PiecePawn, PlayerBlack))
+    board.SetPiece(1, 4, NewPiece(PiecePawn, PlayerBlack))
+    board.SetPiece(4, 6, NewPiece(PieceKnight, PlayerBlack))
+
+    sel, err := board.SelectPiece(4, 3)
+    require.NoError(t, err)
+    require.ElementsMatch(t, []square{sqr(2, 3), sqr(4, 6)}, sel.ThreatenPieces())
+    require.Equal(t, []square{sqr(2, 3)}, sel.LosingCaptures())
+}
@@ -22,8 +22,14 @@ func (sq square) comp(x, y int) bool {
     return sq.x == x && sq.y == y
 }
 
-func (sq square) inBounds() bool {
-    return sq.x >= 0 && sq.y >= 0 && sq.x < BoardSize && sq.y < BoardSize
+// String names the square as on a board of BoardSize ranks.
+// Board.SquareName names it on boards of other heights.
+func (sq square) String() string {
+    return sq.name(BoardSize)
+}
+
+func (sq square) name(height int) string {
+    return fmt.Sprintf("%c%d", 'a'+sq.y, height-sq.x)
 }
 
 func sqr(x, y int) square {
@@ -51,35 +57,72 @@ func (s *Select) ThreatenPieces() []square {
     return s.threatenPieces
 }
 
+// LosingCaptures returns the threatened pieces that cannot be taken without
+// losing material in the exchange that follows.
+func (s *Select) LosingCaptures() []square {
+    losing := make([]square, 0, len(s.threatenPieces))
+    for _, sq := range s.threatenPieces {
+        if s.board.SEE(Move{from: s.selected, to: sq}) < 0 {
+            losing = append(losing, sq)
+        }
+    }
+
+    return losing
+}
+
+// merge adds the moves of other, a selection of the same piece moving in
+// another way, as for the pieces that combine two others.
+func (s Select) merge(other Select) Select {
+    s.possibleMoves = append(s.possibleMoves, other.possibleMoves...)
+    s.threatenPieces = append(s.threatenPieces, other.threatenPieces...)
+    s.checking = s.checking || other.checking
+    return s
+}
+
 func (s *Select) canCastle() bool {
     return s.possibleCastle != nil && len(s.possibleCastle) > 0
 }
 
-func (s *Select) castle(x, y int) *Board {
-    if !s.canCastle() {
-        panic("castling should've already been verified possible!")
-    }
-
-    rookPos := sqr(s.selected.x, 0)
-    dir := 1
-    if x > s.selected.x {
-        rookPos = sqr(s.selected.x, BoardSize-1)
-        dir = -1
+// castle returns the board after castling towards (x, y). The board of the
+// selection may have been changed since, so castling is checked again.
+func (s *Select) castle(x, y int) (*Board, error) {
+    kx, ky := s.selected.x, s.selected.y
+    plan, ok := s.board.castling(kx, ky, y > ky)
+    if !s.canCastle() || !ok {
+        return nil, fmt.Errorf("%w: cannot castle towards %v", IllegalMoveError, sqr(x, y))
     }
 
-    nb, err := s.board.repositionPiece(s.selected.x, s.selected.y, x, y)
-    if err != nil {
-        panic(fmt.Errorf("cannot castle non-king: %v", err))
-    }
-    nb, err = nb.repositionPiece(rookPos.x, rookPos.y, rookPos.x, y+dir)
-    if err != nil {
-        panic(fmt.Errorf("cannot move rook during castlling: %v", err))
+    nb := s.board.copy()
+    king, rook := nb.pieces[kx][ky], nb.pieces[kx][plan.rookY]
+    nb.pieces[kx][ky] = NoPiece()
+    nb.pieces[kx][plan.rookY] = NoPiece()
+    nb.pieces[kx][plan.kingTo] = king
+    nb.pieces[kx][plan.rookTo] = rook
+    for _, y := range []int{ky, plan.rookY, plan.kingTo, plan.rookTo} {
+        nb.active[kx][y] = true
     }
 
-    return nb
+    return nb, nil
 }
 
 func (s *Select) removePossibleMovesDueToCheck() {
+    if !s.board.standardRules() {
+        s.removeIllegalMoves()
+        return
+    }
+
+    // Unless in check, only the king and pinned pieces can expose the king,
+    // and a pinned piece is free to move along its pin.
+    if s.Piece().pieceType != PieceKing && !s.board.InCheck(s.Piece().player) {
+        for _, p := range s.board.pins(s.Piece().player, s.Piece().player) {
+            if p.piece == s.selected {
+                s.possibleMoves = squaresIn(s.possibleMoves, p.ray)
+                s.threatenPieces = squaresIn(s.threatenPieces, p.ray)
+            }
+        }
+        return
+    }
+
     possibles := make([]square, 0, len(s.possibleMoves))
     threatened := make([]square, 0, len(s.threatenPieces))
 
@@ -107,24 +150,55 @@ func (s *Select) removePossibleMovesDueToCheck() {
     s.threatenPieces = threatened
 }
 
+// removeIllegalMoves plays out every move of the selected piece and keeps
+// the ones the variant of the board allows.
+func (s *Select) removeIllegalMoves() {
+    rules := s.board.Variant()
+    player := s.Piece().player
+    legal := func(squares []square, castle bool) []square {
+        kept := make([]square, 0, len(squares))
+        for _, sq := range squares {
+            after, err := s.play(sq, castle)
+            if err != nil {
+                panic("could not look for illegal moves.")
+            }
+            if rules.Legal(s.board, after, Move{from: s.selected, to: sq}, player) {
+                kept = append(kept, sq)
+            }
+        }
+        return kept
+    }
+
+    s.possibleMoves = legal(s.possibleMoves, false)
+    s.threatenPieces = legal(s.threatenPieces, false)
+    s.possibleCastle = legal(s.possibleCastle, true)
+}
+
+func squaresIn(squares, allowed []square) []square {
+    kept := make([]square, 0, len(squares))
+    for _, sq := range squares {
+        for _, a := range allowed {
+            if sq == a {
+                kept = append(kept, sq)
+                break
+            }
+        }
+    }
+
+    return kept
+}
+
 func (s *Select) moveSelectedPiece(toX, toY int) (*Board, error) {
     for _, sq := range s.possibleMoves {
         if sq.comp(toX, toY) {
-            if board, err := s.board.repositionPiece(s.selected.x, s.selected.y, toX, toY); err != nil {
-                return nil, err
-            } else {
-                board.applySpecialRules(s.selected.x, s.selected.y, toX, toY)
-                return board, nil
-            }
+            return s.play(sq, false)
         }
     }
 
     if s.possibleCastle != nil {
         for _, sq := range s.possibleCastle {
             if sq.comp(toX, toY) {
-                board := s.castle(toX, toY)
-                board.applySpecialRules(s.selected.x, s.selected.y, toX, toY)
-                return board, nil
+                return s.play(sq, true)
             }
         }
     }
@@ -132,16 +206,69 @@ func (s *Select) moveSelectedPiece(toX, toY int) (*Board, error) {
     return nil, IllegalMoveError
 }
 
+// play returns the board after moving the selected piece to sq, or
+// castling towards it, with the special rules of the variant applied.
+func (s *Select) play(sq square, castle bool) (*Board, error) {
+    var board *Board
+    var err error
+    if castle {
+        board, err = s.castle(sq.x, sq.y)
+    } else {
+        board, err = s.board.repositionPiece(s.selected.x, s.selected.y, sq.x, sq.y)
+    }
+    if err != nil {
+        return nil, err
+    }
+    board.applySpecialRules(s.board, Move{from: s.selected, to: sq})
+
+    return board, nil
+}
+
+// moves returns the moves of the selected piece, castling included.
+// A pawn reaching its last rank gives one move for each promotion piece,
+// the queen first.
+func (s *Select) moves() []Move {
+    moves := make([]Move, 0, len(s.possibleMoves)+len(s.possibleCastle))
+    for _, sq := range s.possibleMoves {
+        m := Move{from: s.selected, to: sq}
+        if !s.board.isPromotion(m) {
+            moves = append(moves, m)
+            continue
+        }
+        for _, t := range promotionPieces {
+            moves = append(moves, NewPromotion(m.from.x, m.from.y, m.to.x, m.to.y, t))
+        }
+    }
+    for _, sq := range s.possibleCastle {
+        moves = append(moves, Move{from: s.selected, to: sq})
+    }
+    return moves
+}
+
+// keepMoves drops every move of the selected piece that is not in moves.
+func (s *Select) keepMoves(moves []Move) {
+    allowed := make([]square, 0, len(moves))
+    for _, m := range moves {
+        if m.from == s.selected {
+            allowed = append(allowed, m.to)
+        }
+    }
+
+    s.possibleMoves = squaresIn(s.possibleMoves, allowed)
+    s.threatenPieces = squaresIn(s.threatenPieces, allowed)
+    s.possibleCastle = squaresIn(s.possibleCastle, allowed)
+}
+
 func (s *Select) threat(sq square) {
     piece := s.board.GetPiece(sq.x, sq.y)
     if !piece.isPiece() {
         panic("Trying to threat an empty square")
     }
 
-    if piece.pieceType != PieceKing {
+    if s.board.Variant().CanCapture(s.Piece(), piece) {
         s.threatenPieces = append(s.threatenPieces, sq)
         s.possibleMoves = append(s.possibleMoves, sq)
-    } else {
+    } else if piece.pieceType == PieceKing {
         s.checking = true
     }
 }
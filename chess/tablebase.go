@@ -0,0 +1,67 @@
+package chess
+
+import "sync"
+
+// TablebaseResult is the outcome of a position for the side to move, with
+// perfect play from both sides.
+type TablebaseResult string
+
+const (
+    TablebaseWin  TablebaseResult = "Win"
+    TablebaseDraw TablebaseResult = "Draw"
+    TablebaseLoss TablebaseResult = "Loss"
+)
+
+// Tablebase knows the perfect result of endgame positions. The tablebase
+// package builds them; the chess package can't import it, so a tablebase
+// is registered with SetTablebase. There is no Syzygy implementation.
+type Tablebase interface {
+    // Probe returns the result for toMove and the number of plies to mate,
+    // or false if the position isn't in the tables.
+    Probe(board *Board, toMove PlayerType) (TablebaseResult, int, bool)
+    // BestMove returns a move that keeps the result of the position.
+    BestMove(board *Board, toMove PlayerType) (Move, bool)
+}
+
+// TablebaseEntry is what the tables know about a position: its result for
+// the side to move, the plies to mate and a move that keeps the result.
+type TablebaseEntry struct {
+    Result TablebaseResult
+    DTM    int
+    Move   Move
+}
+
+var (
+    tablebaseMu sync.RWMutex
+    tablebase   Tablebase
+)
+
+// SetTablebase registers the tables ProbeTablebase looks positions up in.
+// nil removes them.
+func SetTablebase(tb Tablebase) {
+    tablebaseMu.Lock()
+    defer tablebaseMu.Unlock()
+    tablebase = tb
+}
+
+// ProbeTablebase looks the board up in the registered tables, with toMove
+// to move. It reports false if no tables are registered or they don't hold
+// the position. The move is the zero Move if toMove has none, as when
+// mated.
+func (b *Board) ProbeTablebase(toMove PlayerType) (TablebaseEntry, bool) {
+    tablebaseMu.RLock()
+    tb := tablebase
+    tablebaseMu.RUnlock()
+    if tb == nil {
+        return TablebaseEntry{}, false
+    }
+
+    res, dtm, ok := tb.Probe(b, toMove)
+    if !ok {
+        return TablebaseEntry{}, false
+    }
+    entry := TablebaseEntry{Result: res, DTM: dtm}
+    entry.Move, _ = tb.BestMove(b, toMove)
+
+    return entry, true
+}
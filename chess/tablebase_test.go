@@ -0,0 +1,48 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+// kingsOnly is a tablebase that knows positions with only the two kings on
+// the board, which are draws.
+type kingsOnly struct{}
+
+func (kingsOnly) Probe(board *Board, toMove PlayerType) (TablebaseResult, int, bool) {
+    for x := 0; x < board.Height(); x++ {
+        for y := 0; y < board.Width(); y++ {
+            if t := board.GetPiece(x, y).Type(); t != PieceNone && t != PieceKing {
+                return TablebaseDraw, 0, false
+            }
+        }
+    }
+    return TablebaseDraw, 0, true
+}
+
+func (kingsOnly) BestMove(board *Board, toMove PlayerType) (Move, bool) {
+    moves := board.LegalMoves(toMove)
+    if len(moves) == 0 {
+        return Move{}, false
+    }
+    return moves[0], true
+}
+
+func TestProbeTablebase(t *testing.T) {
+    board, toMove, err := ParseFEN("4k3/8/4K3/8/8/8/8/8 w - - 0 1")
+    require.NoError(t, err)
+    _, ok := board.ProbeTablebase(toMove)
+    require.False(t, ok)
+
+    SetTablebase(kingsOnly{})
+    defer SetTablebase(nil)
+    entry, ok := board.ProbeTablebase(toMove)
+    require.True(t, ok)
+    require.Equal(t, TablebaseDraw, entry.Result)
+    require.Equal(t, board.LegalMoves(toMove)[0], entry.Move)
+
+    board, toMove, err = ParseFEN(StartingFEN)
+    require.NoError(t, err)
+    _, ok = board.ProbeTablebase(toMove)
+    require.False(t, ok)
+}
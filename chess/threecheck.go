@@ -0,0 +1,82 @@
+package chess
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+const ReasonThreeChecks EndReason = "Three checks"
+
+// ThreeCheckLimit is the number of checks that wins a game of Three-check.
+const ThreeCheckLimit = 3
+
+// ThreeCheck is standard chess where giving check for the third time also
+// wins. The checks given so far are kept on the board, and in FEN as a
+// "+W+B" field after the standard ones.
+type ThreeCheck struct {
+    Standard
+}
+
+func (ThreeCheck) Name() string {
+    return "Three-check"
+}
+
+func (v ThreeCheck) ApplySpecialRules(before, after *Board, m Move) {
+    v.Standard.ApplySpecialRules(before, after, m)
+
+    player := before.GetPiece(m.from.x, m.from.y).player
+    if after.InCheck(Opponent(player)) {
+        after.checks[playerIndex(player)]++
+    }
+}
+
+func (v ThreeCheck) Outcome(b *Board, toMove PlayerType) (Outcome, bool) {
+    if b.Checks(Opponent(toMove)) >= ThreeCheckLimit {
+        return Outcome{Winner: Opponent(toMove), Reason: ReasonThreeChecks}, true
+    }
+
+    return v.Standard.Outcome(b, toMove)
+}
+
+// ReadFEN takes the checks given out of the FEN, either as "+W+B" for the
+// checks given or as "W+B" for the checks each side still has to give.
+func (ThreeCheck) ReadFEN(b *Board, fields []string) ([]string, error) {
+    rest := make([]string, 0, len(fields))
+    for i, f := range fields {
+        parts := strings.Split(strings.TrimPrefix(f, "+"), "+")
+        if i < 2 || len(parts) != 2 {
+            rest = append(rest, f)
+            continue
+        }
+
+        for p, part := range parts {
+            n, err := strconv.Atoi(part)
+            if err != nil || n < 0 || n > ThreeCheckLimit {
+                return nil, fmt.Errorf("%w: bad check count %q", InvalidFENError, f)
+            }
+            if !strings.HasPrefix(f, "+") {
+                n = ThreeCheckLimit - n
+            }
+            b.checks[p] = n
+        }
+    }
+
+    return rest, nil
+}
+
+func (ThreeCheck) WriteFEN(b *Board, fields []string) []string {
+    return append(fields, fmt.Sprintf("+%d+%d", b.checks[0], b.checks[1]))
+}
+
+// Checks returns the number of checks player has given.
+func (b *Board) Checks(player PlayerType) int {
+    return b.checks[playerIndex(player)]
+}
+
+func playerIndex(player PlayerType) int {
+    if player == PlayerBlack {
+        return 1
+    }
+    return 0
+}
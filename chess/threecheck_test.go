@@ -0,0 +1,59 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestThreeCheck(t *testing.T) {
+    g, err := NewGame(ThreeCheck{})
+    require.NoError(t, err)
+    require.Equal(t, "Three-check", g.Variant().Name())
+    require.Equal(t, StartingFEN+" +0+0", g.Board().FEN(g.ToMove()))
+
+    playSAN(t, g, "e4", "d5", "Bb5+")
+    require.Equal(t, 1, g.Board().Checks(PlayerWhite))
+    require.Equal(t, 0, g.Board().Checks(PlayerBlack))
+    require.Equal(t, 0, g.Positions()[2].Checks(PlayerWhite))
+
+    playSAN(t, g, "c6", "Bxc6+", "Nxc6", "exd5", "Qxd5", "Qf3", "Qe5+")
+    require.Equal(t, 2, g.Board().Checks(PlayerWhite))
+    require.Equal(t, 1, g.Board().Checks(PlayerBlack))
+    require.Equal(t, "r1b1kbnr/pp2pppp/2n5/4q3/8/5Q2/PPPP1PPP/RNB1K1NR w KQkq - 0 1 +2+1", g.Board().FEN(g.ToMove()))
+    _, over := g.Outcome()
+    require.False(t, over)
+
+    playSAN(t, g, "Qe2", "Nd4", "Qxe5", "Nxc2+")
+    require.Equal(t, 2, g.Board().Checks(PlayerBlack))
+    playSAN(t, g, "Kd1", "Nxa1", "Qxe7+")
+    outcome, over := g.Outcome()
+    require.True(t, over)
+    require.Equal(t, Outcome{Winner: PlayerWhite, Reason: ReasonThreeChecks}, outcome)
+    require.ErrorIs(t, g.Play(NewMove(1, 0, 2, 0)), GameOverError)
+}
+
+func TestThreeCheckFEN(t *testing.T) {
+    g, err := NewGameFromFEN(ThreeCheck{}, "4k3/8/8/8/8/8/8/4K2R w - - 0 1 +2+0")
+    require.NoError(t, err)
+    require.Equal(t, 2, g.Board().Checks(PlayerWhite))
+    playSAN(t, g, "Rh8+")
+    require.Equal(t, "4k2R/8/8/8/8/8/8/4K3 b - - 0 1 +3+0", g.Board().FEN(g.ToMove()))
+    outcome, over := g.Outcome()
+    require.True(t, over)
+    require.Equal(t, PlayerWhite, outcome.Winner)
+
+    // checks still to give, in the older notation
+    g, err = NewGameFromFEN(ThreeCheck{}, "4k3/8/8/8/8/8/8/4K2R w - - 3+1 0 1")
+    require.NoError(t, err)
+    require.Equal(t, 0, g.Board().Checks(PlayerWhite))
+    require.Equal(t, 2, g.Board().Checks(PlayerBlack))
+
+    _, err = NewGameFromFEN(ThreeCheck{}, "4k3/8/8/8/8/8/8/4K2R w - - 0 1 +9+0")
+    require.ErrorIs(t, err, InvalidFENError)
+
+    other, err := NewGameFromFEN(ThreeCheck{}, "4k3/8/8/8/8/8/8/4K2R w - - 0 1 +0+0")
+    require.NoError(t, err)
+    first, err := NewGameFromFEN(ThreeCheck{}, "4k3/8/8/8/8/8/8/4K2R w - - 0 1 +1+0")
+    require.NoError(t, err)
+    require.NotEqual(t, first.Board().Hash(PlayerWhite), other.Board().Hash(PlayerWhite))
+}
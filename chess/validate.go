@@ -0,0 +1,97 @@
+package chess
+
+import "fmt"
+
+var InvalidPositionError = fmt.Errorf("Invalid position")
+
+// Validate checks that the position could come up in a game with toMove to
+// play, by the rules of the variant of the board.
+func (b *Board) Validate(toMove PlayerType) error {
+    return b.Variant().Validate(b, toMove)
+}
+
+// validate checks a position of standard chess: each side has exactly one
+// king, no pawn stands on the first or last rank, the player who just moved
+// is not left in check, and every castling right belongs to a king and rook
+// on their home rank.
+func (b *Board) validate(toMove PlayerType) error {
+    if err := b.validateMaterial(1, 1); err != nil {
+        return err
+    }
+
+    if b.InCheck(Opponent(toMove)) {
+        return fmt.Errorf("%w: %v is in check with %v to move", InvalidPositionError, Opponent(toMove), toMove)
+    }
+
+    return b.validateCastling()
+}
+
+// validateMaterial checks the number of kings of each side, unless it is
+// negative, and that no pawn stands on the first or last rank.
+func (b *Board) validateMaterial(whiteKings, blackKings int) error {
+    if err := b.validatePawns(PlayerNone); err != nil {
+        return err
+    }
+    return b.validateKings(whiteKings, blackKings)
+}
+
+// validatePawns checks that no pawn stands on its last rank, nor on its
+// first rank unless it belongs to firstRank.
+func (b *Board) validatePawns(firstRank PlayerType) error {
+    for i := 0; i < b.height; i++ {
+        for j := 0; j < b.width; j++ {
+            p := b.GetPiece(i, j)
+            if p.pieceType != PiecePawn {
+                continue
+            }
+            if i == b.homeRow(Opponent(p.player)) || i == b.homeRow(p.player) && p.player != firstRank {
+                return fmt.Errorf("%w: pawn on %v", InvalidPositionError, sqr(i, j))
+            }
+        }
+    }
+
+    return nil
+}
+
+// validateKings checks the number of kings of each side, unless it is
+// negative.
+func (b *Board) validateKings(whiteKings, blackKings int) error {
+    kings := map[PlayerType]int{}
+    for i := 0; i < b.height; i++ {
+        for j := 0; j < b.width; j++ {
+            if p := b.GetPiece(i, j); p.pieceType == PieceKing {
+                kings[p.player]++
+            }
+        }
+    }
+
+    for player, want := range map[PlayerType]int{PlayerWhite: whiteKings, PlayerBlack: blackKings} {
+        if want >= 0 && kings[player] != want {
+            return fmt.Errorf("%w: %v has %d kings", InvalidPositionError, player, kings[player])
+        }
+    }
+
+    return nil
+}
+
+// validateCastling checks that in standard chess only a king on the e file
+// has castling rights.
+func (b *Board) validateCastling() error {
+    if b.chess960 {
+        return nil
+    }
+
+    for _, player := range []PlayerType{PlayerWhite, PlayerBlack} {
+        row := b.homeRow(player)
+        for j := 0; j < b.width; j++ {
+            if !b.isPieceOf(sqr(row, j), PieceKing, player) || b.active[row][j] || j == b.width/2 {
+                continue
+            }
+            if b.castleRookY(row, j, true) >= 0 || b.castleRookY(row, j, false) >= 0 {
+                return fmt.Errorf("%w: %v may castle with its king on %v", InvalidPositionError, player, sqr(row, j))
+            }
+        }
+    }
+
+    return nil
+}
@@ -0,0 +1,49 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestValidateStartingPos(t *testing.T) {
+    board := NewChessBoard()
+    board.SetStartingPos()
+    require.NoError(t, board.Validate(PlayerWhite))
+    require.NoError(t, board.Validate(PlayerBlack))
+
+    // the kings face each other on the e file
+    require.Equal(t, NewPiece(PieceKing, PlayerWhite), board.GetPiece(BoardSize-1, 4))
+    require.Equal(t, NewPiece(PieceQueen, PlayerWhite), board.GetPiece(BoardSize-1, 3))
+    require.Equal(t, NewPiece(PieceKing, PlayerBlack), board.GetPiece(0, 4))
+
+    for id := 0; id < Chess960Positions; id++ {
+        board := NewChessBoard()
+        require.NoError(t, board.SetChess960Pos(id))
+        require.NoError(t, board.Validate(PlayerWhite), id)
+    }
+}
+
+func TestValidate(t *testing.T) {
+    for fen, reason := range map[string]string{
+        "8/8/8/8/8/8/8/4K3 w - - 0 1":             "no black king",
+        "4k3/8/8/8/8/8/8/3KK3 w - - 0 1":          "two white kings",
+        "4k3/8/8/8/8/8/8/4K2P w - - 0 1":          "pawn on the first rank",
+        "P3k3/8/8/8/8/8/8/4K3 b - - 0 1":          "pawn on the last rank",
+        "4k3/8/8/8/8/8/8/4K2r b - - 0 1":          "white is in check with black to move",
+        "4k3/8/8/8/8/8/8/R2K3R w KQ - 0 1":        "castling with the king off e1",
+    } {
+        _, _, err := ParseFEN(fen)
+        require.ErrorIs(t, err, InvalidFENError, reason)
+        require.ErrorIs(t, err, InvalidPositionError, reason)
+    }
+
+    board := NewChessBoard()
+    board.SetPiece(0, 4, NewPiece(PieceKing, PlayerBlack))
+    board.SetPiece(7, 4, NewPiece(PieceKing, PlayerWhite))
+    board.SetPiece(7, 0, NewPiece(PieceRook, PlayerBlack))
+    require.NoError(t, board.Validate(PlayerWhite))
+    require.ErrorIs(t, board.Validate(PlayerBlack), InvalidPositionError)
+
+    _, _, err := ParseFEN("4k3/8/8/8/8/8/8/R2K3R w - - 0 1")
+    require.NoError(t, err)
+}
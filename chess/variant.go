@@ -0,0 +1,155 @@
+package chess
+
+import "fmt"
+
+// EndReason says why a game is over.
+type EndReason string
+
+const (
+    ReasonCheckmate EndReason = "Checkmate"
+    ReasonStalemate EndReason = "Stalemate"
+)
+
+// Outcome is the result of a finished game. Winner is PlayerNone for a
+// draw.
+type Outcome struct {
+    Winner PlayerType
+    Reason EndReason
+}
+
+// Variant is a set of rules a game is played by. The board asks its
+// variant for everything that differs between variants: the starting
+// position, which moves are legal, what a move does besides moving a piece,
+// and when the game is over.
+//
+// Variants embed Standard and override the rules they change.
+type Variant interface {
+    // Name returns the name of the variant as used in PGN Variant tags.
+    Name() string
+
+    // Dimensions returns the number of files and ranks of the board.
+    Dimensions() (width, height int)
+
+    // SetStartingPos puts the pieces of a new game on an empty board, or
+    // fails if the board is not of the variant's size.
+    SetStartingPos(b *Board) error
+
+    // DoublePushRanks returns the ranks, counted from player's side, from
+    // which a pawn of player may move two squares.
+    DoublePushRanks(player PlayerType) []int
+
+    // Validate checks that the position could come up in a game with toMove
+    // to play.
+    Validate(b *Board, toMove PlayerType) error
+
+    // CanCapture reports whether piece may take target. Kings that can't
+    // be taken are shown as in check instead.
+    CanCapture(piece, target Piece) bool
+
+    // Legal reports whether player may play m, which turns before into
+    // after. Only moves the pieces can make are asked about.
+    Legal(before, after *Board, m Move, player PlayerType) bool
+
+    // FilterMoves returns the moves of player that remain legal when the
+    // rules look past a single move, such as when captures are compulsory.
+    // moves may be those of one piece only, so a rule that depends on the
+    // other moves has to look at the board itself.
+    FilterMoves(b *Board, player PlayerType, moves []Move) []Move
+
+    // ExtraMoves returns the legal moves of player that are not a piece
+    // moving to a square, such as drops. FilterMoves applies to them too.
+    ExtraMoves(b *Board, player PlayerType) []Move
+
+    // ApplySpecialRules finishes m on after, the board before was turned
+    // into by moving the piece, such as by promoting a pawn.
+    ApplySpecialRules(before, after *Board, m Move)
+
+    // Outcome reports whether the game is over with toMove to play, and how
+    // it ended.
+    Outcome(b *Board, toMove PlayerType) (Outcome, bool)
+}
+
+// Standard is the rules of standard chess.
+type Standard struct{}
+
+func (Standard) Name() string {
+    return "Standard"
+}
+
+func (Standard) Dimensions() (width, height int) {
+    return BoardSize, BoardSize
+}
+
+func (Standard) SetStartingPos(b *Board) error {
+    if b.width != BoardSize || b.height != BoardSize {
+        return fmt.Errorf("%w: %dx%d is not %dx%d", InvalidBoardSizeError, b.width, b.height, BoardSize, BoardSize)
+    }
+    return b.SetStartingPos()
+}
+
+// secondRank is where pawns move two squares from in standard chess.
+var secondRank = []int{2}
+
+func (Standard) DoublePushRanks(player PlayerType) []int {
+    return secondRank
+}
+
+func (Standard) Validate(b *Board, toMove PlayerType) error {
+    return b.validate(toMove)
+}
+
+func (Standard) CanCapture(piece, target Piece) bool {
+    return target.pieceType != PieceKing
+}
+
+func (Standard) Legal(before, after *Board, m Move, player PlayerType) bool {
+    return !after.InCheck(player)
+}
+
+func (Standard) FilterMoves(b *Board, player PlayerType, moves []Move) []Move {
+    return moves
+}
+
+func (Standard) ExtraMoves(b *Board, player PlayerType) []Move {
+    return nil
+}
+
+func (Standard) ApplySpecialRules(before, after *Board, m Move) {
+    if after.promotionNeeded(m.to.x, m.to.y) {
+        t := PieceQueen
+        if m.promotion != "" {
+            t = m.promotion
+        }
+        after.promote(m.to.x, m.to.y, t)
+    }
+}
+
+func (Standard) Outcome(b *Board, toMove PlayerType) (Outcome, bool) {
+    if len(b.LegalMoves(toMove)) > 0 {
+        return Outcome{}, false
+    }
+    if b.InCheck(toMove) {
+        return Outcome{Winner: Opponent(toMove), Reason: ReasonCheckmate}, true
+    }
+    return Outcome{Winner: PlayerNone, Reason: ReasonStalemate}, true
+}
+
+// SetVariant makes the board follow the rules of v.
+func (b *Board) SetVariant(v Variant) {
+    b.variant = v
+}
+
+// Variant returns the rules the board follows, Standard unless set.
+func (b *Board) Variant() Variant {
+    if b.variant == nil {
+        return Standard{}
+    }
+    return b.variant
+}
+
+// standardRules reports whether the board follows the standard rules, which
+// have faster move generation than going through the variant.
+func (b *Board) standardRules() bool {
+    _, ok := b.Variant().(Standard)
+    return ok
+}
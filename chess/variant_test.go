@@ -0,0 +1,95 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+// knightPromotion promotes pawns to knights instead of queens.
+type knightPromotion struct {
+    Standard
+}
+
+func (knightPromotion) ApplySpecialRules(before, after *Board, m Move) {
+    if after.promotionNeeded(m.to.x, m.to.y) {
+        after.promote(m.to.x, m.to.y, PieceKnight)
+    }
+}
+
+// kingStaysHome forbids any king move.
+type kingStaysHome struct {
+    Standard
+}
+
+func (kingStaysHome) Legal(before, after *Board, m Move, player PlayerType) bool {
+    return !before.isPieceOf(m.from, PieceKing, player) && !after.InCheck(player)
+}
+
+// onlyForward only allows pawns to move, two squares at a time.
+type onlyForward struct {
+    Standard
+}
+
+func (onlyForward) FilterMoves(b *Board, player PlayerType, moves []Move) []Move {
+    kept := make([]Move, 0, len(moves))
+    for _, m := range moves {
+        if b.isPieceOf(m.from, PiecePawn, player) && m.from.x-m.to.x == 2 {
+            kept = append(kept, m)
+        }
+    }
+    return kept
+}
+
+// thirdRankPush lets pawns move two squares from the third rank only.
+type thirdRankPush struct {
+    Standard
+}
+
+func (thirdRankPush) DoublePushRanks(player PlayerType) []int {
+    return []int{3}
+}
+
+func TestVariantRules(t *testing.T) {
+    g, err := NewGameFromFEN(knightPromotion{}, "7k/P7/8/8/8/8/8/K7 w - - 0 1")
+    require.NoError(t, err)
+    require.NoError(t, g.Play(NewMove(1, 0, 0, 0)))
+    require.Equal(t, NewPiece(PieceKnight, PlayerWhite), g.Board().GetPiece(0, 0))
+
+    board, _, err := parseFEN("7k/8/8/8/8/8/P7/K7 w - - 0 1", kingStaysHome{}, false)
+    require.NoError(t, err)
+    require.ElementsMatch(t, []Move{NewMove(6, 0, 5, 0), NewMove(6, 0, 4, 0)}, board.LegalMoves(PlayerWhite))
+    sel, err := board.SelectPiece(7, 0)
+    require.NoError(t, err)
+    require.Empty(t, sel.PossibleMoves())
+
+    g, err = NewGame(onlyForward{})
+    require.NoError(t, err)
+    require.Len(t, g.LegalMoves(), 8)
+    sel, err = g.Board().SelectPiece(6, 4)
+    require.NoError(t, err)
+    require.Equal(t, []square{sqr(4, 4)}, sel.PossibleMoves())
+    require.ErrorIs(t, g.Play(NewMove(6, 4, 5, 4)), IllegalMoveError)
+    require.NoError(t, g.Play(NewMove(6, 4, 4, 4)))
+
+    board, _, err = parseFEN("4k3/p7/8/8/8/1P6/P7/4K3 w - - 0 1", thirdRankPush{}, false)
+    require.NoError(t, err)
+    sel, err = board.SelectPiece(6, 0)
+    require.NoError(t, err)
+    require.Equal(t, []square{sqr(5, 0)}, sel.PossibleMoves())
+    sel, err = board.SelectPiece(5, 1)
+    require.NoError(t, err)
+    require.Equal(t, []square{sqr(4, 1), sqr(3, 1)}, sel.PossibleMoves())
+    sel, err = board.SelectPiece(1, 0)
+    require.NoError(t, err)
+    require.Equal(t, []square{sqr(2, 0)}, sel.PossibleMoves())
+}
+
+func TestBoardVariant(t *testing.T) {
+    board := NewChessBoard()
+    require.Equal(t, Standard{}, board.Variant())
+    require.True(t, board.standardRules())
+
+    board.SetVariant(onlyForward{})
+    require.Equal(t, onlyForward{}, board.copy().Variant())
+    require.False(t, board.standardRules())
+}
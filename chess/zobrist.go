@@ -0,0 +1,115 @@
+package chess
+
+var zobristPieces [2][8][MaxBoardSize][MaxBoardSize]uint64
+var zobristCastling [2][MaxBoardSize]uint64
+var zobristBlackToMove uint64
+var zobristChecks [2][3]uint64
+var zobristPockets [2][len(pocketPieces)][maxPocket]uint64
+
+func init() {
+    // fixed seed, so hashes are stable between runs
+    seed := uint64(0x5EED0F60C4E55)
+    next := func() uint64 {
+        // splitmix64
+        seed += 0x9E3779B97F4A7C15
+        z := seed
+        z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+        z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+        return z ^ (z >> 31)
+    }
+
+    for p := range zobristPieces {
+        for t := range zobristPieces[p] {
+            for i := 0; i < MaxBoardSize; i++ {
+                for j := 0; j < MaxBoardSize; j++ {
+                    zobristPieces[p][t][i][j] = next()
+                }
+            }
+        }
+    }
+    for p := range zobristCastling {
+        for y := range zobristCastling[p] {
+            zobristCastling[p][y] = next()
+        }
+    }
+    zobristBlackToMove = next()
+    for p := range zobristChecks {
+        for n := range zobristChecks[p] {
+            zobristChecks[p][n] = next()
+        }
+    }
+    for p := range zobristPockets {
+        for t := range zobristPockets[p] {
+            for n := range zobristPockets[p][t] {
+                zobristPockets[p][t][n] = next()
+            }
+        }
+    }
+}
+
+func zobristPlayerIndex(player PlayerType) int {
+    if player == PlayerBlack {
+        return 1
+    }
+    return 0
+}
+
+func zobristPieceIndex(pieceType PieceType) int {
+    switch pieceType {
+    case PiecePawn:
+        return 0
+    case PieceKnight:
+        return 1
+    case PieceBishop:
+        return 2
+    case PieceRook:
+        return 3
+    case PieceQueen:
+        return 4
+    case PieceArchbishop:
+        return 6
+    case PieceChancellor:
+        return 7
+    default:
+        return 5
+    }
+}
+
+// Hash returns the Zobrist hash of the position with toMove to play. The
+// castling rights are part of it, keyed by the rook each one castles with,
+// but not the squares pieces have left on the way, so that the same
+// position reached by different moves has the same hash. The board does not
+// play en passant, so there is no en passant file to hash.
+func (b *Board) Hash(toMove PlayerType) uint64 {
+    var h uint64
+    for i := 0; i < b.height; i++ {
+        for j := 0; j < b.width; j++ {
+            p := b.GetPiece(i, j)
+            if p.isPiece() {
+                h ^= zobristPieces[zobristPlayerIndex(p.player)][zobristPieceIndex(p.pieceType)][i][j]
+            }
+        }
+    }
+    for _, player := range []PlayerType{PlayerWhite, PlayerBlack} {
+        b.eachCastlingRight(player, func(rookY int, right bool) {
+            h ^= zobristCastling[zobristPlayerIndex(player)][rookY]
+        })
+    }
+    if toMove == PlayerBlack {
+        h ^= zobristBlackToMove
+    }
+    for p, n := range b.checks {
+        if n > 0 {
+            h ^= zobristChecks[p][min(n, len(zobristChecks[p]))-1]
+        }
+    }
+    for p := range b.pockets {
+        for t, n := range b.pockets[p] {
+            if n > 0 {
+                h ^= zobristPockets[p][t][min(n, maxPocket)-1]
+            }
+        }
+    }
+
+    return h
+}
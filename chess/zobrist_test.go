@@ -0,0 +1,52 @@
+package chess
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+    board := NewChessBoard()
+    board.SetStartingPos()
+    other := NewChessBoard()
+    other.SetStartingPos()
+
+    require.Equal(t, board.Hash(PlayerWhite), other.Hash(PlayerWhite))
+    require.NotEqual(t, board.Hash(PlayerWhite), board.Hash(PlayerBlack))
+
+    other.SetPiece(4, 4, NewPiece(PiecePawn, PlayerWhite))
+    require.NotEqual(t, board.Hash(PlayerWhite), other.Hash(PlayerWhite))
+
+    // the same pieces, but the king has moved and came back
+    nb, err := board.repositionPiece(7, 4, 5, 4)
+    require.NoError(t, err)
+    nb, err = nb.repositionPiece(5, 4, 7, 4)
+    require.NoError(t, err)
+    require.NotEqual(t, board.Hash(PlayerWhite), nb.Hash(PlayerWhite))
+    require.Equal(t, board.copy().Hash(PlayerWhite), board.Hash(PlayerWhite))
+}
+
+func TestHashTranspositions(t *testing.T) {
+    start, err := NewGame(Standard{})
+    require.NoError(t, err)
+    g, err := NewGame(Standard{})
+    require.NoError(t, err)
+    playSAN(t, g, "Nf3", "Nf6", "Ng1", "Ng8")
+    require.Equal(t, start.Board().FEN(start.ToMove()), g.Board().FEN(g.ToMove()))
+    require.Equal(t, start.Board().Hash(start.ToMove()), g.Board().Hash(g.ToMove()))
+
+    first, err := NewGame(Standard{})
+    require.NoError(t, err)
+    playSAN(t, first, "e4", "Nf6", "d4", "e6", "Nc3")
+    second, err := NewGame(Standard{})
+    require.NoError(t, err)
+    playSAN(t, second, "Nc3", "e6", "d4", "Nf6", "e4")
+    require.Equal(t, first.Board().Hash(first.ToMove()), second.Board().Hash(second.ToMove()))
+
+    // a rook that went away and came back has lost its right
+    playSAN(t, g, "Nf3", "Nf6", "Rg1", "Ng8", "Rh1", "Nf6")
+    playSAN(t, start, "Nf3", "Nf6", "Ng1", "Ng8", "Nf3", "Nf6")
+    require.Equal(t, "rnbqkb1r/pppppppp/5n2/8/8/5N2/PPPPPPPP/RNBQKB1R w KQkq - 0 1", start.Board().FEN(start.ToMove()))
+    require.Equal(t, "rnbqkb1r/pppppppp/5n2/8/8/5N2/PPPPPPPP/RNBQKB1R w Qkq - 0 1", g.Board().FEN(g.ToMove()))
+    require.NotEqual(t, start.Board().Hash(start.ToMove()), g.Board().Hash(g.ToMove()))
+}
@@ -0,0 +1,65 @@
+package engine
+
+import "goChess/chess"
+
+// Bonuses of the evaluation, in centipawns.
+const (
+    centreBonus  = 3
+    advanceBonus = 5
+)
+
+// Evaluate scores the position in centipawns from the side of toMove: the
+// material of both sides, pieces near the centre and pawns that advanced.
+// Kings count for their placement only, so that positions without one, as
+// in Horde, are scored by the pieces that are there.
+func Evaluate(b *chess.Board, toMove chess.PlayerType) int {
+    score := 0
+    for i := 0; i < b.Height(); i++ {
+        for j := 0; j < b.Width(); j++ {
+            p := b.GetPiece(i, j)
+            if p.Type() == chess.PieceNone {
+                continue
+            }
+            v := placement(b, p, i, j)
+            if p.Type() != chess.PieceKing {
+                v += p.Value()
+            }
+            if p.Player() == toMove {
+                score += v
+            } else {
+                score -= v
+            }
+        }
+    }
+
+    return score
+}
+
+// placement is the bonus of piece p for standing on square (x, y).
+func placement(b *chess.Board, p chess.Piece, x, y int) int {
+    switch p.Type() {
+    case chess.PiecePawn:
+        advanced := b.Height() - 2 - x
+        if p.Player() == chess.PlayerBlack {
+            advanced = x - 1
+        }
+        return advanceBonus * advanced
+    case chess.PieceKing, chess.PieceRook:
+        return 0
+    default:
+        return centreBonus * centrality(b, x, y)
+    }
+}
+
+// centrality grows the closer square (x, y) is to the centre of the board,
+// from 1 in the corners.
+func centrality(b *chess.Board, x, y int) int {
+    dx, dy := 2*x-(b.Height()-1), 2*y-(b.Width()-1)
+    if dx < 0 {
+        dx = -dx
+    }
+    if dy < 0 {
+        dy = -dy
+    }
+    return (b.Height() + b.Width() - dx - dy) / 2
+}
@@ -0,0 +1,142 @@
+package engine
+
+import (
+    "sort"
+    "goChess/chess"
+)
+
+const MaxPly = 128
+
+const squares = chess.MaxBoardSize * chess.MaxBoardSize
+
+// Move ordering scores, from the first moves to try to the last.
+const (
+    hashMoveScore    = 1 << 30
+    goodCaptureScore = 1 << 28
+    killerScore      = 1 << 26
+    counterMoveScore = 1 << 25
+    badCaptureScore  = -(1 << 28)
+)
+
+// maxHistory is the history score at which the table is aged, low enough
+// that a quiet move never ties with the counter-move.
+const maxHistory = counterMoveScore / 2
+
+// MoveOrderer keeps the killer, counter-move and history tables a search
+// learns from beta cutoffs and sorts moves so the likely best come first.
+// The zero chess.Move stands for "no move" in every table.
+type MoveOrderer struct {
+    killers      [MaxPly][2]chess.Move
+    counterMoves [squares][squares]chess.Move
+    history      [2][squares][squares]int
+}
+
+func NewMoveOrderer() *MoveOrderer {
+    return &MoveOrderer{}
+}
+
+func squareIndex(x, y int) int {
+    return x*chess.MaxBoardSize + y
+}
+
+func fromIndex(m chess.Move) int {
+    return squareIndex(m.From().X(), m.From().Y())
+}
+
+func toIndex(m chess.Move) int {
+    return squareIndex(m.To().X(), m.To().Y())
+}
+
+func playerIndex(player chess.PlayerType) int {
+    if player == chess.PlayerBlack {
+        return 1
+    }
+    return 0
+}
+
+func (o *MoveOrderer) Clear() {
+    *o = MoveOrderer{}
+}
+
+// AddKiller remembers a quiet move that caused a cutoff at ply.
+func (o *MoveOrderer) AddKiller(ply int, m chess.Move) {
+    if ply >= MaxPly || o.killers[ply][0] == m {
+        return
+    }
+    o.killers[ply][1] = o.killers[ply][0]
+    o.killers[ply][0] = m
+}
+
+// AddCounterMove remembers m as the refutation of the opponent's prev.
+func (o *MoveOrderer) AddCounterMove(prev, m chess.Move) {
+    if prev == (chess.Move{}) {
+        return
+    }
+    o.counterMoves[fromIndex(prev)][toIndex(prev)] = m
+}
+
+// AddHistory rewards a quiet move of player that caused a cutoff at the
+// given remaining depth.
+func (o *MoveOrderer) AddHistory(player chess.PlayerType, m chess.Move, depth int) {
+    h := &o.history[playerIndex(player)][fromIndex(m)][toIndex(m)]
+    *h += depth * depth
+    if *h > maxHistory {
+        o.AgeHistory()
+    }
+}
+
+// AgeHistory halves the history table so older cutoffs count less.
+func (o *MoveOrderer) AgeHistory() {
+    for p := range o.history {
+        for f := range o.history[p] {
+            for t := range o.history[p][f] {
+                o.history[p][f][t] /= 2
+            }
+        }
+    }
+}
+
+// Order sorts moves in place: the hash move, winning and equal captures by
+// MVV-LVA, killers, the counter-move of prev, quiet moves by history and
+// finally captures that lose material according to SEE.
+func (o *MoveOrderer) Order(b *chess.Board, moves []chess.Move, hashMove chess.Move, ply int, prev chess.Move) {
+    scores := make(map[chess.Move]int, len(moves))
+    for _, m := range moves {
+        scores[m] = o.score(b, m, hashMove, ply, prev)
+    }
+
+    sort.SliceStable(moves, func(i, j int) bool {
+        return scores[moves[i]] > scores[moves[j]]
+    })
+}
+
+func (o *MoveOrderer) score(b *chess.Board, m chess.Move, hashMove chess.Move, ply int, prev chess.Move) int {
+    if m == hashMove {
+        return hashMoveScore
+    }
+
+    piece := b.GetPiece(m.From().X(), m.From().Y())
+    victim := b.GetPiece(m.To().X(), m.To().Y())
+    if victim.Type() != chess.PieceNone {
+        mvvlva := victim.Value()*10 - piece.Value()/10
+        if b.SEE(m) < 0 {
+            return badCaptureScore + mvvlva
+        }
+        return goodCaptureScore + mvvlva
+    }
+
+    if ply < MaxPly {
+        if m == o.killers[ply][0] {
+            return killerScore
+        }
+        if m == o.killers[ply][1] {
+            return killerScore - 1
+        }
+    }
+
+    if prev != (chess.Move{}) && m == o.counterMoves[fromIndex(prev)][toIndex(prev)] {
+        return counterMoveScore
+    }
+
+    return o.history[playerIndex(piece.Player())][fromIndex(m)][toIndex(m)]
+}
@@ -0,0 +1,93 @@
+package engine
+
+import (
+    "testing"
+    "goChess/chess"
+    "github.com/stretchr/testify/require"
+)
+
+func orderingBoard() *chess.Board {
+    board := chess.NewChessBoard()
+    board.SetPiece(7, 7, chess.NewPiece(chess.PieceKing, chess.PlayerWhite))
+    board.SetPiece(0, 0, chess.NewPiece(chess.PieceKing, chess.PlayerBlack))
+    board.SetPiece(4, 3, chess.NewPiece(chess.PieceQueen, chess.PlayerWhite))
+    board.SetPiece(6, 1, chess.NewPiece(chess.PieceKnight, chess.PlayerWhite))
+    // a pawn defended by a pawn and an undefended rook
+    board.SetPiece(2, 3, chess.NewPiece(chess.PiecePawn, chess.PlayerBlack))
+    board.SetPiece(1, 4, chess.NewPiece(chess.PiecePawn, chess.PlayerBlack))
+    board.SetPiece(4, 6, chess.NewPiece(chess.PieceRook, chess.PlayerBlack))
+    return board
+}
+
+func TestOrderCaptures(t *testing.T) {
+    board := orderingBoard()
+    moves := board.LegalMoves(chess.PlayerWhite)
+    o := NewMoveOrderer()
+    o.Order(board, moves, chess.Move{}, 0, chess.Move{})
+
+    require.Equal(t, chess.NewMove(4, 3, 4, 6), moves[0])
+    require.Equal(t, chess.NewMove(4, 3, 2, 3), moves[len(moves)-1])
+}
+
+func TestOrderHashMoveAndKillers(t *testing.T) {
+    board := orderingBoard()
+    moves := board.LegalMoves(chess.PlayerWhite)
+    o := NewMoveOrderer()
+
+    hash := chess.NewMove(6, 1, 4, 2)
+    killer := chess.NewMove(4, 3, 5, 3)
+    counter := chess.NewMove(7, 7, 6, 7)
+    history := chess.NewMove(6, 1, 5, 3)
+    prev := chess.NewMove(1, 1, 2, 1)
+
+    o.AddKiller(3, killer)
+    o.AddCounterMove(prev, counter)
+    o.AddHistory(chess.PlayerWhite, history, 4)
+    o.Order(board, moves, hash, 3, prev)
+
+    require.Equal(t, hash, moves[0])
+    require.Equal(t, chess.NewMove(4, 3, 4, 6), moves[1])
+    require.Equal(t, killer, moves[2])
+    require.Equal(t, counter, moves[3])
+    require.Equal(t, history, moves[4])
+
+    // killers only apply to their own ply
+    o.Order(board, moves, chess.Move{}, 4, chess.Move{})
+    require.NotEqual(t, killer, moves[1])
+}
+
+func TestKillerSlots(t *testing.T) {
+    o := NewMoveOrderer()
+    a := chess.NewMove(0, 0, 0, 1)
+    b := chess.NewMove(0, 0, 0, 2)
+    c := chess.NewMove(0, 0, 0, 3)
+
+    o.AddKiller(1, a)
+    o.AddKiller(1, a)
+    require.Equal(t, [2]chess.Move{a, {}}, o.killers[1])
+    o.AddKiller(1, b)
+    o.AddKiller(1, c)
+    require.Equal(t, [2]chess.Move{c, b}, o.killers[1])
+
+    o.Clear()
+    require.Equal(t, [2]chess.Move{}, o.killers[1])
+}
+
+func TestHistoryStaysBelowCounterMove(t *testing.T) {
+    board := orderingBoard()
+    moves := board.LegalMoves(chess.PlayerWhite)
+    o := NewMoveOrderer()
+
+    counter := chess.NewMove(7, 7, 6, 7)
+    history := chess.NewMove(6, 1, 5, 3)
+    prev := chess.NewMove(1, 1, 2, 1)
+    o.AddCounterMove(prev, counter)
+    for i := 0; i < 10000; i++ {
+        o.AddHistory(chess.PlayerWhite, history, MaxPly)
+        require.Less(t, o.history[0][fromIndex(history)][toIndex(history)], counterMoveScore)
+    }
+
+    o.Order(board, moves, chess.Move{}, 0, prev)
+    require.Equal(t, counter, moves[1])
+    require.Equal(t, history, moves[2])
+}
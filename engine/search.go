@@ -0,0 +1,655 @@
+package engine
+
+import (
+    "context"
+    "sort"
+    "sync"
+    "sync/atomic"
+    "goChess/chess"
+    "goChess/tablebase"
+)
+
+// MateScore is the score of mating right away. A mate in n plies scores
+// MateScore-n, being mated in n plies -(MateScore-n).
+const MateScore = 100000
+
+// MaxDepth is the deepest iteration a search without a depth limit runs.
+const MaxDepth = MaxPly / 2
+
+const (
+    infinity = MateScore + 1
+    // scores beyond mateBound are mates
+    mateBound = MateScore - MaxPly
+)
+
+// MaxThreads is the most goroutines a search runs on.
+const MaxThreads = 256
+
+// checkInterval is the number of nodes between checks of the limits.
+const checkInterval = 1024
+
+// Margins of the pruning techniques, in centipawns.
+const (
+    futilityMargin        = 150
+    reverseFutilityMargin = 120
+    aspirationWindow      = 50
+)
+
+// nullMoveReduction is how much shallower the search after a null move is.
+const nullMoveReduction = 2
+
+// Options turn the pruning, reduction and extension techniques of the search
+// on and off, so that each can be measured in matches against the others.
+type Options struct {
+    // NullMove lets the opponent move twice; if that still fails high, so
+    // will a real move.
+    NullMove           bool
+    // LateMoveReductions searches quiet moves ordered late less deep.
+    LateMoveReductions bool
+    // Futility skips quiet moves near the leaves that can't raise alpha.
+    Futility           bool
+    // ReverseFutility returns early near the leaves when the evaluation is
+    // far above beta.
+    ReverseFutility    bool
+    // CheckExtensions searches positions in check one ply deeper.
+    CheckExtensions    bool
+    // AspirationWindows starts every iteration with a narrow window around
+    // the score of the previous one.
+    AspirationWindows  bool
+}
+
+// DefaultOptions turns on every technique.
+func DefaultOptions() Options {
+    return Options{
+        NullMove: true,
+        LateMoveReductions: true,
+        Futility: true,
+        ReverseFutility: true,
+        CheckExtensions: true,
+        AspirationWindows: true,
+    }
+}
+
+// Limits bound a search. A zero field does not limit it.
+type Limits struct {
+    Depth int
+    Nodes uint64
+    Time  *TimeManager
+    // OnIteration, if set, is called with the result of every iteration
+    // the main thread completes, for a GUI to show while the search goes
+    // on. Nodes counts the nodes of all threads so far.
+    OnIteration func(Result)
+}
+
+// Line is a line of play found by a search, the moves from the position
+// searched and their score from the side to move.
+type Line struct {
+    Moves []chess.Move
+    Score int
+    Depth int
+}
+
+// Result is what a search found: the best move, its score from the side to
+// move, the depth of the last completed iteration and the line of best play
+// from the position. Lines holds the best lines with different first moves,
+// as many as the engine's MultiPV, best first.
+type Result struct {
+    Move  chess.Move
+    Score int
+    Depth int
+    PV    []chess.Move
+    Lines []Line
+    Nodes uint64
+}
+
+// Engine searches positions with iterative deepening alpha-beta. The
+// transposition table is kept from one search to the next, and shared by
+// the threads of a search (Lazy SMP).
+type Engine struct {
+    tt        *TranspositionTable
+    options   Options
+    threads   int
+    multiPV   int
+    tablebase *tablebase.Set
+}
+
+func NewEngine(hashMB int) *Engine {
+    return &Engine{tt: NewTranspositionTable(hashMB), options: DefaultOptions(), threads: 1, multiPV: 1}
+}
+
+func (e *Engine) Threads() int {
+    return e.threads
+}
+
+// SetThreads sets how many goroutines search at the same time, between 1
+// and MaxThreads.
+func (e *Engine) SetThreads(n int) {
+    e.threads = min(max(n, 1), MaxThreads)
+}
+
+func (e *Engine) Options() Options {
+    return e.options
+}
+
+func (e *Engine) SetOptions(options Options) {
+    e.options = options
+}
+
+func (e *Engine) MultiPV() int {
+    return e.multiPV
+}
+
+// SetMultiPV sets how many lines, each with a different first move, a
+// search reports.
+func (e *Engine) SetMultiPV(n int) {
+    e.multiPV = max(n, 1)
+}
+
+// SetHash resizes the transposition table, which clears it.
+func (e *Engine) SetHash(sizeMB int) {
+    e.tt.Resize(sizeMB)
+}
+
+// NewGame forgets what earlier searches learned.
+func (e *Engine) NewGame() {
+    e.tt.Clear()
+}
+
+// searcher holds the state of a single search thread.
+type searcher struct {
+    ctx     context.Context
+    tt      *TranspositionTable
+    orderer *MoveOrderer
+    options Options
+    limits  Limits
+    // tables probed instead of searching, if any
+    tablebase *tablebase.Set
+    nodes   uint64
+    // nodes searched by all threads, counted checkInterval at a time
+    total   *atomic.Uint64
+    stopped bool
+    // pv[ply] is the best line found from the node at ply
+    pv      [MaxPly + 1][]chess.Move
+    // hashes of the positions from the root to the current node
+    path    []uint64
+    // root moves of the lines already found in this iteration
+    excluded []chess.Move
+}
+
+// Search looks for the best move of toMove on board, deepening the search
+// one ply at a time until a limit is reached or ctx is done. Every thread
+// but the first runs its own iterative deepening, to fill the shared
+// transposition table, and the result is the deepest completed iteration
+// of any thread. A position in the engine's tables is played from them
+// without searching.
+func (e *Engine) Search(ctx context.Context, board *chess.Board, toMove chess.PlayerType, limits Limits) Result {
+    e.tt.NewSearch()
+
+    moves := board.LegalMoves(toMove)
+    if limits.Time != nil {
+        limits.Time.SetLegalMoves(len(moves))
+    }
+    if len(moves) == 0 {
+        return Result{}
+    }
+
+    if e.multiPV == 1 {
+        if result, ok := probeRoot(e.tablebase, board, toMove); ok {
+            return result
+        }
+    }
+
+    maxDepth := limits.Depth
+    if maxDepth <= 0 || maxDepth > MaxDepth {
+        maxDepth = MaxDepth
+    }
+
+    lines := min(e.multiPV, len(moves))
+    ctx, cancel := context.WithCancel(ctx)
+    defer cancel()
+    total := &atomic.Uint64{}
+    searchers := make([]*searcher, e.threads)
+    results := make([]Result, e.threads)
+    for i := range searchers {
+        searchers[i] = &searcher{ctx: ctx, tt: e.tt, orderer: NewMoveOrderer(), options: e.options, limits: limits, tablebase: e.tablebase, total: total}
+    }
+
+    var wg sync.WaitGroup
+    for i := 1; i < e.threads; i++ {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            // helpers leave the clock and the reports to the main thread,
+            // and half of them start a ply deeper so that the threads don't
+            // move in step
+            searchers[i].limits.Time = nil
+            searchers[i].limits.OnIteration = nil
+            results[i] = searchers[i].iterate(board, toMove, 1+i%2, maxDepth, moves[0], lines)
+        }(i)
+    }
+    results[0] = searchers[0].iterate(board, toMove, 1, maxDepth, moves[0], lines)
+    cancel()
+    wg.Wait()
+
+    result := results[0]
+    for _, r := range results[1:] {
+        if r.Depth > result.Depth {
+            result = r
+        }
+    }
+    result.Nodes = 0
+    for _, s := range searchers {
+        result.Nodes += s.nodes
+    }
+
+    return result
+}
+
+// iterate runs iterative deepening from depth first to last, finding the
+// given number of lines in every iteration. It returns the result of the
+// last completed iteration, or firstMove if there is none.
+func (s *searcher) iterate(board *chess.Board, toMove chess.PlayerType, first, last int, firstMove chess.Move, lines int) Result {
+    result := Result{Move: firstMove}
+    for depth := first; depth <= last; depth++ {
+        found := s.findLines(board, toMove, depth, lines, result.Lines)
+        if s.stopped || len(found) == 0 {
+            break
+        }
+
+        changed := result.Depth > 0 && found[0].Moves[0] != result.Move
+        result = Result{
+            Move: found[0].Moves[0],
+            Score: found[0].Score,
+            Depth: depth,
+            PV: found[0].Moves,
+            Lines: found,
+        }
+        if s.limits.OnIteration != nil {
+            progress := result
+            progress.Nodes = max(s.total.Load(), s.nodes)
+            s.limits.OnIteration(progress)
+        }
+        if s.limits.Time != nil {
+            s.limits.Time.OnIteration(changed)
+            if s.limits.Time.ShouldStop() {
+                break
+            }
+        }
+    }
+
+    return result
+}
+
+// findLines searches the root depth plies deep once for every line, each
+// time leaving out the first moves of the lines found before. previous are
+// the lines of the last iteration, whose scores centre the windows.
+func (s *searcher) findLines(board *chess.Board, toMove chess.PlayerType, depth, lines int, previous []Line) []Line {
+    found := make([]Line, 0, lines)
+    s.excluded = s.excluded[:0]
+    for len(found) < lines {
+        centre := 0
+        if len(found) < len(previous) {
+            centre = previous[len(found)].Score
+        }
+        score := s.aspirate(board, toMove, depth, centre)
+        // a variant may end the game while there are moves left
+        if s.stopped || len(s.pv[0]) == 0 {
+            break
+        }
+        found = append(found, Line{Moves: append([]chess.Move(nil), s.pv[0]...), Score: score, Depth: depth})
+        s.excluded = append(s.excluded, s.pv[0][0])
+    }
+
+    sort.SliceStable(found, func(i, j int) bool {
+        return found[i].Score > found[j].Score
+    })
+    return found
+}
+
+// aspirate searches the root depth plies deep. With aspiration windows the
+// window is centred on the score of the previous iteration, and widened
+// until the score falls inside.
+func (s *searcher) aspirate(b *chess.Board, toMove chess.PlayerType, depth, previous int) int {
+    if !s.options.AspirationWindows || depth < 4 || previous > mateBound || previous < -mateBound {
+        return s.negamax(b, toMove, depth, -infinity, infinity, 0, chess.Move{})
+    }
+
+    delta := aspirationWindow
+    alpha, beta := previous-delta, previous+delta
+    for {
+        score := s.negamax(b, toMove, depth, alpha, beta, 0, chess.Move{})
+        switch {
+        case s.stopped:
+            return score
+        case score <= alpha:
+            alpha = max(score-delta, -infinity)
+        case score >= beta:
+            beta = min(score+delta, infinity)
+        default:
+            return score
+        }
+        delta *= 2
+    }
+}
+
+// negamax returns the score of board for toMove, searched depth plies deep
+// within the window alpha to beta. prev is the move that led to board, the
+// zero Move after a null move.
+func (s *searcher) negamax(b *chess.Board, toMove chess.PlayerType, depth, alpha, beta, ply int, prev chess.Move) int {
+    if ply > 0 {
+        if score, ok := probe(s.tablebase, b, toMove, ply); ok {
+            s.pv[ply] = s.pv[ply][:0]
+            return score
+        }
+    }
+
+    inCheck := b.InCheck(toMove)
+    if inCheck && s.options.CheckExtensions {
+        depth++
+    }
+    if depth <= 0 || ply >= MaxPly {
+        return s.quiesce(b, toMove, alpha, beta, ply)
+    }
+
+    s.pv[ply] = s.pv[ply][:0]
+    if s.visit() {
+        return 0
+    }
+
+    key := b.Hash(toMove)
+    if ply > 0 && s.repeated(key) {
+        return 0
+    }
+
+    entry, found := s.tt.Probe(key)
+    if found && ply > 0 && entry.Depth() >= depth {
+        score := scoreFromTT(entry.Score(), ply)
+        switch entry.Bound() {
+        case BoundExact:
+            return score
+        case BoundLower:
+            if score >= beta {
+                return score
+            }
+        case BoundUpper:
+            if score <= alpha {
+                return score
+            }
+        }
+    }
+
+    moves := b.LegalMoves(toMove)
+    if score, over := outcome(b, toMove, moves, ply); over {
+        return score
+    }
+
+    s.path = append(s.path, key)
+    defer func() { s.path = s.path[:len(s.path)-1] }()
+
+    opponent := chess.Opponent(toMove)
+    pvNode := beta-alpha > 1
+    eval := 0
+    if !inCheck {
+        eval = Evaluate(b, toMove)
+    }
+    prune := !pvNode && !inCheck && beta < mateBound && beta > -mateBound
+
+    if prune && s.options.ReverseFutility && depth <= 3 && eval-reverseFutilityMargin*depth >= beta {
+        return eval
+    }
+
+    if prune && s.options.NullMove && depth > nullMoveReduction && eval >= beta && prev != (chess.Move{}) && hasPieces(b, toMove) {
+        score := -s.negamax(b, opponent, depth-1-nullMoveReduction, -beta, -beta+1, ply+1, chess.Move{})
+        if s.stopped {
+            return 0
+        }
+        if score >= beta {
+            return beta
+        }
+    }
+
+    futile := prune && s.options.Futility && depth <= 2 && eval+futilityMargin*depth <= alpha
+
+    s.orderer.Order(b, moves, entry.Move(), ply, prev)
+    best, bestMove := -infinity, chess.Move{}
+    bound := BoundUpper
+    // i counts the moves searched so far
+    i := -1
+    for _, m := range moves {
+        if ply == 0 && s.isExcluded(m) {
+            continue
+        }
+        nb, err := b.MakeMove(m)
+        if err != nil {
+            continue
+        }
+        i++
+
+        late := i > 0 && quiet(b, m) && !nb.InCheck(opponent)
+        if futile && late {
+            if eval > best {
+                best = eval
+            }
+            continue
+        }
+
+        // after the first move, moves are searched with a null window to
+        // prove that they are worse, and again if they turn out better
+        score := alpha + 1
+        if i > 0 {
+            reduction := 0
+            if s.options.LateMoveReductions && late && !inCheck && depth >= 3 && i >= 3 {
+                reduction = 1
+                if i >= 6 {
+                    reduction = depth / 3
+                }
+            }
+            score = -s.negamax(nb, opponent, depth-1-reduction, -alpha-1, -alpha, ply+1, m)
+            if score > alpha && reduction > 0 && !s.stopped {
+                score = -s.negamax(nb, opponent, depth-1, -alpha-1, -alpha, ply+1, m)
+            }
+        }
+        if score > alpha && !s.stopped {
+            score = -s.negamax(nb, opponent, depth-1, -beta, -alpha, ply+1, m)
+        }
+        if s.stopped {
+            return 0
+        }
+        if score <= best {
+            continue
+        }
+
+        best, bestMove = score, m
+        if score <= alpha {
+            continue
+        }
+        alpha = score
+        s.pv[ply] = append(append(s.pv[ply][:0], m), s.pv[ply+1]...)
+        bound = BoundExact
+        if score >= beta {
+            bound = BoundLower
+            if quiet(b, m) {
+                s.orderer.AddKiller(ply, m)
+                s.orderer.AddCounterMove(prev, m)
+                s.orderer.AddHistory(toMove, m, depth)
+            }
+            break
+        }
+    }
+
+    // with moves left out the root's score is not that of the position
+    if ply > 0 || len(s.excluded) == 0 {
+        s.tt.Store(key, depth, bound, scoreToTT(best, ply), bestMove)
+    }
+    return best
+}
+
+// quiesce searches captures only until the position is quiet, so that the
+// evaluation never stops in the middle of an exchange. Captures that lose
+// material are not tried. In check every move is tried, so that mates are
+// found.
+func (s *searcher) quiesce(b *chess.Board, toMove chess.PlayerType, alpha, beta, ply int) int {
+    if ply <= MaxPly {
+        s.pv[ply] = s.pv[ply][:0]
+    }
+    if s.visit() {
+        return 0
+    }
+    if ply >= MaxPly {
+        return Evaluate(b, toMove)
+    }
+
+    inCheck := b.InCheck(toMove)
+    moves := b.LegalMoves(toMove)
+    if score, over := outcome(b, toMove, moves, ply); over {
+        return score
+    }
+
+    best := -infinity
+    if !inCheck {
+        best = Evaluate(b, toMove)
+        if best >= beta {
+            return best
+        }
+        if best > alpha {
+            alpha = best
+        }
+
+        captures := moves[:0]
+        for _, m := range moves {
+            if !quiet(b, m) && b.SEE(m) >= 0 {
+                captures = append(captures, m)
+            }
+        }
+        moves = captures
+    }
+    s.orderer.Order(b, moves, chess.Move{}, MaxPly, chess.Move{})
+
+    for _, m := range moves {
+        nb, err := b.MakeMove(m)
+        if err != nil {
+            continue
+        }
+
+        score := -s.quiesce(nb, chess.Opponent(toMove), -beta, -alpha, ply+1)
+        if s.stopped {
+            return 0
+        }
+        if score > best {
+            best = score
+        }
+        if score > alpha {
+            alpha = score
+            if score >= beta {
+                break
+            }
+        }
+    }
+
+    return best
+}
+
+// visit counts a node and reports whether the search has to stop.
+func (s *searcher) visit() bool {
+    s.nodes++
+    if s.stopped || s.nodes%checkInterval != 0 {
+        return s.stopped
+    }
+
+    total := s.total.Add(checkInterval)
+    if s.limits.Nodes > 0 && total >= s.limits.Nodes {
+        s.stopped = true
+    }
+    if s.ctx.Err() != nil {
+        s.stopped = true
+    }
+    if s.limits.Time != nil && s.limits.Time.HardStop() {
+        s.stopped = true
+    }
+    return s.stopped
+}
+
+func (s *searcher) isExcluded(m chess.Move) bool {
+    for _, e := range s.excluded {
+        if e == m {
+            return true
+        }
+    }
+    return false
+}
+
+// repeated reports whether the position with key came up before on the way
+// from the root, which makes it a draw.
+func (s *searcher) repeated(key uint64) bool {
+    for _, k := range s.path {
+        if k == key {
+            return true
+        }
+    }
+    return false
+}
+
+// outcome returns the score of a finished game, moves being the legal moves
+// of toMove.
+func outcome(b *chess.Board, toMove chess.PlayerType, moves []chess.Move, ply int) (int, bool) {
+    if _, standard := b.Variant().(chess.Standard); standard && len(moves) > 0 {
+        return 0, false
+    }
+
+    o, over := b.Variant().Outcome(b, toMove)
+    switch {
+    case !over:
+        return 0, false
+    case o.Winner == toMove:
+        return MateScore - ply, true
+    case o.Winner == chess.Opponent(toMove):
+        return -(MateScore - ply), true
+    default:
+        return 0, true
+    }
+}
+
+// hasPieces reports whether player has a piece besides pawns and the king,
+// without which a null move may miss a zugzwang.
+func hasPieces(b *chess.Board, player chess.PlayerType) bool {
+    for i := 0; i < b.Height(); i++ {
+        for j := 0; j < b.Width(); j++ {
+            p := b.GetPiece(i, j)
+            if p.Player() == player && p.Type() != chess.PieceNone && p.Type() != chess.PiecePawn && p.Type() != chess.PieceKing {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// quiet reports whether m neither captures nor promotes.
+func quiet(b *chess.Board, m chess.Move) bool {
+    if m.IsDrop() {
+        return true
+    }
+    to := m.To()
+    return b.GetPiece(to.X(), to.Y()).Type() == chess.PieceNone && m.Promotion() == chess.PieceNone
+}
+
+// scoreToTT turns a mate score relative to the root into one relative to
+// the node at ply, so that it stays right when found at another ply.
+func scoreToTT(score, ply int) int {
+    switch {
+    case score > mateBound:
+        return score + ply
+    case score < -mateBound:
+        return score - ply
+    }
+    return score
+}
+
+func scoreFromTT(score, ply int) int {
+    switch {
+    case score > mateBound:
+        return score - ply
+    case score < -mateBound:
+        return score + ply
+    }
+    return score
+}
@@ -0,0 +1,220 @@
+package engine
+
+import (
+    "context"
+    "testing"
+    "time"
+    "goChess/chess"
+    "goChess/tablebase"
+    "github.com/stretchr/testify/require"
+)
+
+func searchFEN(t *testing.T, e *Engine, fen string, limits Limits) Result {
+    board, toMove, err := chess.ParseFEN(fen)
+    require.NoError(t, err)
+    return e.Search(context.Background(), board, toMove, limits)
+}
+
+func TestSearchMate(t *testing.T) {
+    e := NewEngine(1)
+    r := searchFEN(t, e, "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", Limits{Depth: 2})
+    require.Equal(t, chess.NewMove(7, 0, 0, 0), r.Move)
+    require.Equal(t, MateScore-1, r.Score)
+
+    // two rooks mate in two moves, three plies
+    r = searchFEN(t, e, "4k3/8/8/8/8/8/R7/1R5K w - - 0 1", Limits{Depth: 4})
+    require.Equal(t, MateScore-3, r.Score)
+    require.Len(t, r.PV, 3)
+
+    // and black sees it coming
+    board, toMove, err := chess.ParseFEN("4k3/8/8/8/8/8/R7/1R5K w - - 0 1")
+    require.NoError(t, err)
+    board, err = board.MakeMove(r.Move)
+    require.NoError(t, err)
+    require.Equal(t, -(MateScore-2), e.Search(context.Background(), board, chess.Opponent(toMove), Limits{Depth: 3}).Score)
+}
+
+func TestSearchWinsMaterial(t *testing.T) {
+    e := NewEngine(1)
+    // the knight takes the queen and the queen takes back
+    r := searchFEN(t, e, "4k3/8/2p5/3q4/8/4N3/8/3QK3 w - - 0 1", Limits{Depth: 3})
+    require.Equal(t, chess.NewMove(5, 4, 3, 3), r.Move)
+    require.Greater(t, r.Score, chess.QueenValue/2)
+    require.Equal(t, 3, r.Depth)
+}
+
+func TestSearchGameOver(t *testing.T) {
+    e := NewEngine(1)
+    r := searchFEN(t, e, "7k/5Q2/6K1/8/8/8/8/8 b - - 0 1", Limits{Depth: 3})
+    require.Equal(t, Result{}, r)
+}
+
+func TestSearchLimits(t *testing.T) {
+    e := NewEngine(1)
+    r := searchFEN(t, e, chess.StartingFEN, Limits{Nodes: 2000})
+    require.Less(t, r.Nodes, uint64(2000+checkInterval))
+    require.Greater(t, r.Depth, 0)
+    require.NotEqual(t, chess.Move{}, r.Move)
+}
+
+func TestSearchReusesTable(t *testing.T) {
+    e := NewEngine(1)
+    first := searchFEN(t, e, chess.StartingFEN, Limits{Depth: 3})
+    again := searchFEN(t, e, chess.StartingFEN, Limits{Depth: 3})
+    require.Equal(t, first.Move, again.Move)
+    require.Less(t, again.Nodes, first.Nodes)
+
+    e.NewGame()
+    require.Equal(t, first.Nodes, searchFEN(t, e, chess.StartingFEN, Limits{Depth: 3}).Nodes)
+}
+
+// techniques lists every search option with a copy of the options that
+// only turns it on.
+func techniques() map[string]Options {
+    return map[string]Options{
+        "null move": {NullMove: true},
+        "late move reductions": {LateMoveReductions: true},
+        "futility": {Futility: true},
+        "reverse futility": {ReverseFutility: true},
+        "check extensions": {CheckExtensions: true},
+        "aspiration windows": {AspirationWindows: true},
+        "all": DefaultOptions(),
+    }
+}
+
+const middlegameFEN = "r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4"
+
+func TestSearchOptions(t *testing.T) {
+    plain := NewEngine(1)
+    plain.SetOptions(Options{})
+    base := searchFEN(t, plain, middlegameFEN, Limits{Depth: 4})
+
+    for name, options := range techniques() {
+        t.Run(name, func(t *testing.T) {
+            e := NewEngine(1)
+            e.SetOptions(options)
+            require.Equal(t, options, e.Options())
+
+            // every technique changes the tree but not what is found
+            r := searchFEN(t, e, middlegameFEN, Limits{Depth: 4})
+            require.NotEqual(t, base.Nodes, r.Nodes)
+
+            r = searchFEN(t, e, "4k3/8/8/8/8/8/R7/1R5K w - - 0 1", Limits{Depth: 4})
+            require.Equal(t, MateScore-3, r.Score)
+            r = searchFEN(t, e, "4k3/8/2p5/3q4/8/4N3/8/3QK3 w - - 0 1", Limits{Depth: 3})
+            require.Equal(t, chess.NewMove(5, 4, 3, 3), r.Move)
+        })
+    }
+
+    e := NewEngine(1)
+    r := searchFEN(t, e, middlegameFEN, Limits{Depth: 4})
+    require.Less(t, r.Nodes, base.Nodes/2)
+}
+
+func TestLazySMP(t *testing.T) {
+    e := NewEngine(1)
+    e.SetThreads(4)
+    require.Equal(t, 4, e.Threads())
+
+    r := searchFEN(t, e, "4k3/8/8/8/8/8/R7/1R5K w - - 0 1", Limits{Depth: 4})
+    require.Equal(t, MateScore-3, r.Score)
+    r = searchFEN(t, e, "4k3/8/2p5/3q4/8/4N3/8/3QK3 w - - 0 1", Limits{Depth: 3})
+    require.Equal(t, chess.NewMove(5, 4, 3, 3), r.Move)
+    require.GreaterOrEqual(t, r.Depth, 3)
+
+    e.SetThreads(0)
+    require.Equal(t, 1, e.Threads())
+    e.SetThreads(MaxThreads + 1)
+    require.Equal(t, MaxThreads, e.Threads())
+}
+
+func TestSearchContext(t *testing.T) {
+    e := NewEngine(1)
+    e.SetThreads(2)
+    board, toMove, err := chess.ParseFEN(middlegameFEN)
+    require.NoError(t, err)
+
+    ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+    defer cancel()
+    start := time.Now()
+    r := e.Search(ctx, board, toMove, Limits{})
+    require.Less(t, time.Since(start), 2*time.Second)
+    require.Greater(t, r.Depth, 0)
+    require.Contains(t, board.LegalMoves(toMove), r.Move)
+
+    // a search that is stopped before it starts still has a move to play
+    cancel()
+    r = e.Search(ctx, board, toMove, Limits{})
+    require.Contains(t, board.LegalMoves(toMove), r.Move)
+}
+
+func TestMultiPV(t *testing.T) {
+    e := NewEngine(1)
+    e.SetMultiPV(3)
+    require.Equal(t, 3, e.MultiPV())
+
+    r := searchFEN(t, e, "4k3/8/2p5/3q4/8/4N3/8/3QK3 w - - 0 1", Limits{Depth: 3})
+    require.Len(t, r.Lines, 3)
+    require.Equal(t, r.PV, r.Lines[0].Moves)
+    require.Equal(t, chess.NewMove(5, 4, 3, 3), r.Lines[0].Moves[0])
+    first := map[chess.Move]bool{}
+    for i, l := range r.Lines {
+        require.Equal(t, 3, l.Depth)
+        first[l.Moves[0]] = true
+        if i > 0 {
+            require.LessOrEqual(t, l.Score, r.Lines[i-1].Score)
+        }
+    }
+    require.Len(t, first, 3)
+
+    // the king has two moves, so there are only two lines
+    e.SetThreads(2)
+    r = searchFEN(t, e, "k7/8/8/2Q5/8/8/8/7K b - - 0 1", Limits{Depth: 2})
+    require.Len(t, r.Lines, 2)
+
+    e.SetMultiPV(1)
+    r = searchFEN(t, e, "4k3/8/2p5/3q4/8/4N3/8/3QK3 w - - 0 1", Limits{Depth: 3})
+    require.Len(t, r.Lines, 1)
+}
+
+func TestSearchTablebase(t *testing.T) {
+    s := tablebase.NewSet()
+    _, err := s.Generate("KRK")
+    require.NoError(t, err)
+    e := NewEngine(1)
+    e.SetTablebase(s)
+
+    // the root is played from the table, with the mate to the end
+    board, toMove, err := chess.ParseFEN("8/8/8/3k4/8/8/8/R3K3 w - - 0 1")
+    require.NoError(t, err)
+    _, dtm, ok := s.Probe(board, toMove)
+    require.True(t, ok)
+    r := e.Search(context.Background(), board, toMove, Limits{Depth: 1})
+    require.Equal(t, MateScore-dtm, r.Score)
+    require.Len(t, r.PV, dtm)
+    best, _ := s.BestMove(board, toMove)
+    require.Equal(t, best, r.Move)
+
+    // taking the knight leads into the table, where the mate is known
+    r = searchFEN(t, e, "8/8/8/3k4/8/8/3n4/R3K3 w - - 0 1", Limits{Depth: 1})
+    require.Equal(t, chess.NewMove(7, 4, 6, 3), r.Move)
+    require.Greater(t, r.Score, mateBound)
+
+    e.SetTablebase(nil)
+    r = searchFEN(t, e, "8/8/8/3k4/8/8/3n4/R3K3 w - - 0 1", Limits{Depth: 1})
+    require.Less(t, r.Score, mateBound)
+}
+
+func TestOnIteration(t *testing.T) {
+    e := NewEngine(1)
+    e.SetThreads(2)
+    var depths []int
+    limits := Limits{Depth: 4, OnIteration: func(r Result) {
+        depths = append(depths, r.Depth)
+        require.NotEmpty(t, r.Lines)
+        require.Greater(t, r.Nodes, uint64(0))
+    }}
+    r := searchFEN(t, e, middlegameFEN, limits)
+    require.Equal(t, []int{1, 2, 3, 4}, depths)
+    require.Equal(t, 4, r.Depth)
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+    "goChess/chess"
+    "goChess/tablebase"
+)
+
+// SetTablebase lets the search probe tables: positions with their material
+// in the set are scored from the tables instead of being searched, and a
+// root position in the set is played from them right away. nil turns
+// probing off.
+func (e *Engine) SetTablebase(s *tablebase.Set) {
+    e.tablebase = s
+}
+
+// probe looks the position up in the tables, and returns its score from the
+// side of toMove with mates counted from the root.
+func probe(s *tablebase.Set, b *chess.Board, toMove chess.PlayerType, ply int) (int, bool) {
+    // the tables know the rules of standard chess only
+    if _, standard := b.Variant().(chess.Standard); s == nil || !standard {
+        return 0, false
+    }
+    res, dtm, ok := s.Probe(b, toMove)
+    if !ok {
+        return 0, false
+    }
+
+    switch res {
+    case tablebase.Win:
+        return MateScore - ply - dtm, true
+    case tablebase.Loss:
+        return -(MateScore - ply - dtm), true
+    }
+    return 0, true
+}
+
+// probeRoot plays the position from the tables if they hold it, with the
+// line of best play until mate as the principal variation.
+func probeRoot(s *tablebase.Set, b *chess.Board, toMove chess.PlayerType) (Result, bool) {
+    score, ok := probe(s, b, toMove, 0)
+    if !ok {
+        return Result{}, false
+    }
+
+    var pv []chess.Move
+    for len(pv) < MaxPly {
+        m, ok := s.BestMove(b, toMove)
+        if !ok {
+            break
+        }
+        next, err := b.MakeMove(m)
+        if err != nil {
+            break
+        }
+        pv = append(pv, m)
+        // a draw needs a single move, and a capture may leave the tables
+        if score == 0 {
+            break
+        }
+        b, toMove = next, chess.Opponent(toMove)
+    }
+    if len(pv) == 0 {
+        return Result{}, false
+    }
+
+    return Result{
+        Move: pv[0],
+        Score: score,
+        Depth: len(pv),
+        PV: pv,
+        Lines: []Line{{Moves: pv, Score: score, Depth: len(pv)}},
+    }, true
+}
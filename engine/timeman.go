@@ -0,0 +1,174 @@
+package engine
+
+import (
+    "sync"
+    "time"
+    "goChess/chess"
+)
+
+// MoveOverhead is kept in reserve on every move for communication lag.
+const MoveOverhead = 30 * time.Millisecond
+
+// defaultMovesToGo is the number of moves the remaining time is spread over
+// when the time control does not say.
+const defaultMovesToGo = 30
+
+// TimeControl holds the clock state of a UCI "go" command.
+type TimeControl struct {
+    WTime     time.Duration
+    BTime     time.Duration
+    WInc      time.Duration
+    BInc      time.Duration
+    MovesToGo int
+    MoveTime  time.Duration
+    Infinite  bool
+}
+
+// TimeManager decides how long the search spends on a move. The search is
+// expected to check HardStop often and ShouldStop after every iteration of
+// iterative deepening.
+type TimeManager struct {
+    mu          sync.Mutex
+    now         func() time.Time
+    start       time.Time
+    soft        time.Duration
+    hard        time.Duration
+    limited     bool
+    pondering   bool
+    forced      bool
+    instability float64
+}
+
+func NewTimeManager(tc TimeControl, side chess.PlayerType, ponder bool) *TimeManager {
+    return newTimeManager(tc, side, ponder, time.Now)
+}
+
+func newTimeManager(tc TimeControl, side chess.PlayerType, ponder bool, now func() time.Time) *TimeManager {
+    tm := &TimeManager{now: now, start: now(), pondering: ponder}
+
+    left, inc := tc.WTime, tc.WInc
+    if side == chess.PlayerBlack {
+        left, inc = tc.BTime, tc.BInc
+    }
+
+    switch {
+    case tc.Infinite:
+    case tc.MoveTime > 0:
+        tm.limited = true
+        tm.soft = atLeast(tc.MoveTime - MoveOverhead, time.Millisecond)
+        tm.hard = tm.soft
+    case left > 0:
+        tm.limited = true
+        mtg := tc.MovesToGo
+        if mtg <= 0 || mtg > defaultMovesToGo {
+            mtg = defaultMovesToGo
+        }
+        available := atLeast(left - MoveOverhead, time.Millisecond)
+
+        tm.soft = available/time.Duration(mtg) + inc*3/4
+        tm.hard = tm.soft * 4
+        // the hard limit never takes the whole clock: even the last move
+        // before the time control keeps half of it as a margin
+        ceiling := available / 3
+        if mtg == 1 {
+            ceiling = available / 2
+        }
+        if tm.hard > ceiling {
+            tm.hard = ceiling
+        }
+        if tm.soft > tm.hard {
+            tm.soft = tm.hard
+        }
+    }
+
+    return tm
+}
+
+func atLeast(d, min time.Duration) time.Duration {
+    if d < min {
+        return min
+    }
+    return d
+}
+
+func (tm *TimeManager) SoftLimit() time.Duration {
+    tm.mu.Lock()
+    defer tm.mu.Unlock()
+    return tm.softLimit()
+}
+
+func (tm *TimeManager) HardLimit() time.Duration {
+    return tm.hard
+}
+
+// softLimit stretches the soft limit while the best move keeps changing.
+func (tm *TimeManager) softLimit() time.Duration {
+    soft := time.Duration(float64(tm.soft) * (1 + tm.instability/2))
+    if soft > tm.hard {
+        return tm.hard
+    }
+    return soft
+}
+
+func (tm *TimeManager) Elapsed() time.Duration {
+    tm.mu.Lock()
+    defer tm.mu.Unlock()
+    return tm.now().Sub(tm.start)
+}
+
+// SetLegalMoves tells the manager how many moves there are to choose from.
+// With a single legal move there is nothing to think about.
+func (tm *TimeManager) SetLegalMoves(n int) {
+    tm.mu.Lock()
+    defer tm.mu.Unlock()
+    tm.forced = n == 1
+}
+
+// OnIteration is called after each completed iteration with whether the
+// best move differs from the previous iteration.
+func (tm *TimeManager) OnIteration(bestMoveChanged bool) {
+    tm.mu.Lock()
+    defer tm.mu.Unlock()
+    tm.instability /= 2
+    if bestMoveChanged {
+        tm.instability += 1
+    }
+}
+
+// ShouldStop reports whether another iteration should not be started.
+func (tm *TimeManager) ShouldStop() bool {
+    tm.mu.Lock()
+    defer tm.mu.Unlock()
+    if tm.pondering {
+        return false
+    }
+    if tm.forced {
+        return true
+    }
+    return tm.limited && tm.now().Sub(tm.start) >= tm.softLimit()
+}
+
+// HardStop reports whether the search must be aborted right away.
+func (tm *TimeManager) HardStop() bool {
+    tm.mu.Lock()
+    defer tm.mu.Unlock()
+    if tm.pondering {
+        return false
+    }
+    return tm.limited && tm.now().Sub(tm.start) >= tm.hard
+}
+
+func (tm *TimeManager) Pondering() bool {
+    tm.mu.Lock()
+    defer tm.mu.Unlock()
+    return tm.pondering
+}
+
+// PonderHit switches a pondering search to a normal one: the opponent played
+// the expected move, so the clock starts running now.
+func (tm *TimeManager) PonderHit() {
+    tm.mu.Lock()
+    defer tm.mu.Unlock()
+    tm.pondering = false
+    tm.start = tm.now()
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+    "testing"
+    "time"
+    "goChess/chess"
+    "github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+    t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+    return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+    c.t = c.t.Add(d)
+}
+
+func TestTimeAllocation(t *testing.T) {
+    clock := &fakeClock{t: time.Unix(0, 0)}
+    tc := TimeControl{
+        WTime: 60*time.Second + MoveOverhead,
+        BTime: 10*time.Second + MoveOverhead,
+        WInc: 2*time.Second,
+    }
+
+    white := newTimeManager(tc, chess.PlayerWhite, false, clock.now)
+    require.Equal(t, 2*time.Second + 1500*time.Millisecond, white.SoftLimit())
+    require.Equal(t, 14*time.Second, white.HardLimit())
+
+    black := newTimeManager(tc, chess.PlayerBlack, false, clock.now)
+    require.Less(t, black.SoftLimit(), white.SoftLimit())
+    require.LessOrEqual(t, black.HardLimit(), 10*time.Second/3)
+
+    // the last move before the time control keeps a margin
+    tc.MovesToGo = 1
+    last := newTimeManager(tc, chess.PlayerBlack, false, clock.now)
+    require.Equal(t, 5*time.Second, last.SoftLimit())
+    require.Equal(t, 5*time.Second, last.HardLimit())
+
+    tc = TimeControl{WTime: 100*time.Millisecond + MoveOverhead, MovesToGo: 1}
+    last = newTimeManager(tc, chess.PlayerWhite, false, clock.now)
+    require.Equal(t, 50*time.Millisecond, last.HardLimit())
+}
+
+func TestMoveTimeAndInfinite(t *testing.T) {
+    clock := &fakeClock{t: time.Unix(0, 0)}
+    tm := newTimeManager(TimeControl{MoveTime: time.Second}, chess.PlayerWhite, false, clock.now)
+    require.Equal(t, time.Second - MoveOverhead, tm.HardLimit())
+    clock.advance(time.Second)
+    require.True(t, tm.HardStop())
+
+    tm = newTimeManager(TimeControl{Infinite: true, WTime: time.Second}, chess.PlayerWhite, false, clock.now)
+    clock.advance(time.Hour)
+    require.False(t, tm.ShouldStop())
+    require.False(t, tm.HardStop())
+}
+
+func TestStopping(t *testing.T) {
+    clock := &fakeClock{t: time.Unix(0, 0)}
+    tm := newTimeManager(TimeControl{WTime: 30*time.Second + MoveOverhead}, chess.PlayerWhite, false, clock.now)
+    require.Equal(t, time.Second, tm.SoftLimit())
+
+    clock.advance(900 * time.Millisecond)
+    require.False(t, tm.ShouldStop())
+    clock.advance(100 * time.Millisecond)
+    require.True(t, tm.ShouldStop())
+    require.False(t, tm.HardStop())
+
+    clock.advance(3 * time.Second)
+    require.True(t, tm.HardStop())
+}
+
+func TestUnstableBestMove(t *testing.T) {
+    clock := &fakeClock{t: time.Unix(0, 0)}
+    tm := newTimeManager(TimeControl{WTime: 30*time.Second + MoveOverhead}, chess.PlayerWhite, false, clock.now)
+
+    tm.OnIteration(true)
+    require.Equal(t, 1500*time.Millisecond, tm.SoftLimit())
+    tm.OnIteration(true)
+    require.Equal(t, 1750*time.Millisecond, tm.SoftLimit())
+
+    clock.advance(1200 * time.Millisecond)
+    require.False(t, tm.ShouldStop())
+
+    tm.OnIteration(false)
+    tm.OnIteration(false)
+    require.True(t, tm.ShouldStop())
+}
+
+func TestForcedMove(t *testing.T) {
+    clock := &fakeClock{t: time.Unix(0, 0)}
+    tm := newTimeManager(TimeControl{WTime: time.Minute}, chess.PlayerWhite, false, clock.now)
+    require.False(t, tm.ShouldStop())
+    tm.SetLegalMoves(1)
+    require.True(t, tm.ShouldStop())
+    require.False(t, tm.HardStop())
+}
+
+func TestPonder(t *testing.T) {
+    clock := &fakeClock{t: time.Unix(0, 0)}
+    tm := newTimeManager(TimeControl{WTime: 30*time.Second + MoveOverhead}, chess.PlayerWhite, true, clock.now)
+    require.True(t, tm.Pondering())
+
+    clock.advance(time.Minute)
+    require.False(t, tm.ShouldStop())
+    require.False(t, tm.HardStop())
+
+    tm.PonderHit()
+    require.False(t, tm.Pondering())
+    require.False(t, tm.ShouldStop())
+    require.Equal(t, time.Duration(0), tm.Elapsed())
+
+    clock.advance(time.Second)
+    require.True(t, tm.ShouldStop())
+}
@@ -0,0 +1,191 @@
+package engine
+
+import (
+    "sync"
+    "sync/atomic"
+    "unsafe"
+    "goChess/chess"
+)
+
+// Limits of the table size in megabytes, as offered by the UCI Hash option.
+const (
+    DefaultHashMB = 16
+    MinHashMB     = 1
+    MaxHashMB     = 4096
+)
+
+const bucketSize = 4
+const lockStripes = 1024
+
+type Bound uint8
+
+const (
+    BoundNone Bound = iota
+    BoundExact
+    BoundLower
+    BoundUpper
+)
+
+type Entry struct {
+    key   uint64
+    move  chess.Move
+    score int32
+    depth int16
+    bound Bound
+    age   uint8
+}
+
+type bucket [bucketSize]Entry
+
+// TranspositionTable is a fixed size hash table of search results keyed by
+// the Zobrist hash of the position. It is safe for concurrent use; Resize
+// and Clear must not be called while a search is running.
+type TranspositionTable struct {
+    buckets []bucket
+    mask    uint64
+    locks   [lockStripes]sync.Mutex
+    age     atomic.Uint32
+}
+
+func NewTranspositionTable(sizeMB int) *TranspositionTable {
+    tt := &TranspositionTable{}
+    tt.Resize(sizeMB)
+    return tt
+}
+
+// Resize reallocates the table to fit in sizeMB megabytes, dropping all
+// entries.
+func (tt *TranspositionTable) Resize(sizeMB int) {
+    if sizeMB < MinHashMB {
+        sizeMB = MinHashMB
+    }
+    if sizeMB > MaxHashMB {
+        sizeMB = MaxHashMB
+    }
+
+    n := uint64(sizeMB) * 1024 * 1024 / uint64(unsafe.Sizeof(bucket{}))
+    size := uint64(1)
+    for size*2 <= n {
+        size *= 2
+    }
+
+    tt.buckets = make([]bucket, size)
+    tt.mask = size - 1
+    tt.age.Store(0)
+}
+
+func (tt *TranspositionTable) Clear() {
+    for i := range tt.buckets {
+        tt.buckets[i] = bucket{}
+    }
+    tt.age.Store(0)
+}
+
+// NewSearch ages the table so that entries of earlier searches are
+// replaced first. It may be called while other goroutines use the table.
+func (tt *TranspositionTable) NewSearch() {
+    tt.age.Add(1)
+}
+
+// currentAge is the age of the running search, wrapping around like the
+// age of the entries.
+func (tt *TranspositionTable) currentAge() uint8 {
+    return uint8(tt.age.Load())
+}
+
+func (tt *TranspositionTable) Probe(key uint64) (Entry, bool) {
+    idx := key & tt.mask
+    lock := &tt.locks[idx%lockStripes]
+    lock.Lock()
+    defer lock.Unlock()
+
+    b := &tt.buckets[idx]
+    for i := range b {
+        if b[i].bound != BoundNone && b[i].key == key {
+            b[i].age = tt.currentAge()
+            return b[i], true
+        }
+    }
+
+    return Entry{}, false
+}
+
+// Store saves a search result. Within the bucket of the key an entry of the
+// same position is updated, otherwise the shallowest and oldest entry is
+// replaced.
+func (tt *TranspositionTable) Store(key uint64, depth int, bound Bound, score int, move chess.Move) {
+    idx := key & tt.mask
+    lock := &tt.locks[idx%lockStripes]
+    lock.Lock()
+    defer lock.Unlock()
+
+    age := tt.currentAge()
+    entry := Entry{
+        key: key,
+        move: move,
+        score: int32(score),
+        depth: int16(depth),
+        bound: bound,
+        age: age,
+    }
+
+    b := &tt.buckets[idx]
+    replace := 0
+    for i := range b {
+        if b[i].bound != BoundNone && b[i].key == key {
+            if depth >= int(b[i].depth) || bound == BoundExact || b[i].age != age {
+                b[i] = entry
+            }
+            return
+        }
+        if b[i].bound == BoundNone {
+            b[i] = entry
+            return
+        }
+        if p, best := priority(b[i], age), priority(b[replace], age); p < best || (p == best && b[i].age != age) {
+            replace = i
+        }
+    }
+    b[replace] = entry
+}
+
+// priority of keeping an entry: deep entries of the current search first.
+func priority(e Entry, age uint8) int {
+    return int(e.depth) - 8*int(age-e.age)
+}
+
+// Hashfull returns how many of the first thousand entries are in use by the
+// current search, in permille.
+func (tt *TranspositionTable) Hashfull() int {
+    used, total := 0, 0
+    age := tt.currentAge()
+    for i := 0; i < len(tt.buckets) && total < 1000; i++ {
+        lock := &tt.locks[uint64(i)%lockStripes]
+        lock.Lock()
+        for _, e := range tt.buckets[i] {
+            total++
+            if e.bound != BoundNone && e.age == age {
+                used++
+            }
+        }
+        lock.Unlock()
+    }
+
+    return used * 1000 / total
+}
+
+func (e Entry) Move() chess.Move {
+    return e.move
+}
+
+func (e Entry) Score() int {
+    return int(e.score)
+}
+
+func (e Entry) Depth() int {
+    return int(e.depth)
+}
+
+func (e Entry) Bound() Bound {
+    return e.bound
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+    "sync"
+    "testing"
+    "goChess/chess"
+    "github.com/stretchr/testify/require"
+)
+
+func TestStoreProbe(t *testing.T) {
+    tt := NewTranspositionTable(1)
+    move := chess.NewMove(6, 4, 4, 4)
+
+    _, ok := tt.Probe(42)
+    require.False(t, ok)
+
+    tt.Store(42, 5, BoundExact, 31, move)
+    e, ok := tt.Probe(42)
+    require.True(t, ok)
+    require.Equal(t, 5, e.Depth())
+    require.Equal(t, BoundExact, e.Bound())
+    require.Equal(t, 31, e.Score())
+    require.Equal(t, move, e.Move())
+
+    // a shallower bound does not overwrite a deeper result
+    tt.Store(42, 3, BoundLower, 10, move)
+    e, _ = tt.Probe(42)
+    require.Equal(t, 5, e.Depth())
+
+    tt.Store(42, 7, BoundUpper, -4, move)
+    e, _ = tt.Probe(42)
+    require.Equal(t, 7, e.Depth())
+    require.Equal(t, BoundUpper, e.Bound())
+
+    tt.Clear()
+    _, ok = tt.Probe(42)
+    require.False(t, ok)
+}
+
+func TestReplacement(t *testing.T) {
+    tt := NewTranspositionTable(1)
+    stride := tt.mask + 1
+    move := chess.NewMove(0, 0, 1, 1)
+
+    // fill one bucket
+    for i := 0; i < bucketSize; i++ {
+        tt.Store(1+uint64(i)*stride, 10+i, BoundExact, 0, move)
+    }
+    // the shallowest entry goes first
+    tt.Store(1+uint64(bucketSize)*stride, 1, BoundExact, 0, move)
+    _, ok := tt.Probe(1)
+    require.False(t, ok)
+    _, ok = tt.Probe(1+stride)
+    require.True(t, ok)
+
+    // entries of an earlier search are replaced before deeper ones
+    tt.Clear()
+    for i := 0; i < bucketSize; i++ {
+        tt.Store(1+uint64(i)*stride, 10+i, BoundExact, 0, move)
+    }
+    tt.NewSearch()
+    for i := 0; i < bucketSize; i++ {
+        tt.Store(1+uint64(bucketSize+i)*stride, 5, BoundExact, 0, move)
+    }
+    for i := 0; i < bucketSize; i++ {
+        _, ok = tt.Probe(1+uint64(i)*stride)
+        require.False(t, ok)
+        _, ok = tt.Probe(1+uint64(bucketSize+i)*stride)
+        require.True(t, ok)
+    }
+}
+
+func TestConcurrentAccess(t *testing.T) {
+    tt := NewTranspositionTable(1)
+    var wg sync.WaitGroup
+    for g := 0; g < 8; g++ {
+        wg.Add(1)
+        go func(g int) {
+            defer wg.Done()
+            for i := 0; i < 10000; i++ {
+                key := uint64(g*10000 + i)
+                tt.Store(key, i%20, BoundLower, i, chess.NewMove(0, 0, 0, 1))
+                if e, ok := tt.Probe(key); ok {
+                    require.Equal(t, i, e.Score())
+                }
+            }
+        }(g)
+    }
+    wg.Add(1)
+    go func() {
+        defer wg.Done()
+        for i := 0; i < 100; i++ {
+            tt.NewSearch()
+            tt.Hashfull()
+        }
+    }()
+    wg.Wait()
+
+    for key := uint64(0); key < 1000; key++ {
+        tt.Store(key, 1, BoundExact, 0, chess.Move{})
+    }
+    require.Greater(t, tt.Hashfull(), 0)
+}
+
+func TestResize(t *testing.T) {
+    tt := NewTranspositionTable(0)
+    small := len(tt.buckets)
+    tt.Resize(4)
+    require.Equal(t, 4*small, len(tt.buckets))
+}
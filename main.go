@@ -1,15 +1,74 @@
 package main
 
 import (
-	//"fmt"
+	"bufio"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+	"goChess/book"
 	"goChess/chess"
+	"goChess/engine"
 	"goChess/printer"
+	"goChess/uci"
 )
 
 func main() {
-	board := chess.NewChessBoard()
-	board.SetStartingPos()
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run starts the mode named by the first argument: "uci" for the UCI
+// protocol, "play" for a game against the engine. Without one it shows
+// the starting position.
+func run(args []string) error {
+	if len(args) == 0 {
+		board := chess.NewChessBoard()
+		if err := board.SetStartingPos(); err != nil {
+			return err
+		}
+		return printer.PrintChessBoard(board)
+	}
+
+	switch args[0] {
+	case "uci":
+		return uci.New(os.Stdout).Run(os.Stdin)
+	case "play":
+		return runPlay(args[1:])
+	}
+	return fmt.Errorf("unknown mode %q, expected uci or play", args[0])
+}
+
+func runPlay(args []string) error {
+	flags := flag.NewFlagSet("play", flag.ContinueOnError)
+	bookPath := flags.String("book", "", "Polyglot opening book to pick the engine's first moves from")
+	depth := flags.Int("depth", 4, "how many plies the engine searches")
+	black := flags.Bool("black", false, "play the black pieces")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
 
+	p := &player{
+		in: bufio.NewScanner(os.Stdin),
+		out: os.Stdout,
+		engine: engine.NewEngine(engine.DefaultHashMB),
+		depth: *depth,
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+		human: chess.PlayerWhite,
+	}
+	if *black {
+		p.human = chess.PlayerBlack
+	}
+	if *bookPath != "" {
+		b, err := book.Open(*bookPath)
+		if err != nil {
+			return err
+		}
+		p.book = b
+	}
 
-	printer.PrintChessBoard(board)
+	return p.play()
 }
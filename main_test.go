@@ -1,12 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
 	"testing"
 	"goChess/chess"
 	"goChess/printer"
 	"github.com/stretchr/testify/require"
 )
 
+var update = flag.Bool("update", false, "update golden files")
+
 type squareTest struct {
     x int
     y int
@@ -258,7 +264,17 @@ func TestShowBoards(t *testing.T) {
 	        sel, err := board.SelectPiece(c.selected.x, c.selected.y)
 	        require.NoError(t, err)
 
-	        printer.PrintSelect(&sel)
+	        var buf bytes.Buffer
+	        require.NoError(t, printer.TextRenderer{}.Render(&buf, printer.NewView(&sel)))
+
+	        golden := filepath.Join("testdata", "show_boards", c.testName+".golden")
+	        if *update {
+	        	require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0755))
+	        	require.NoError(t, os.WriteFile(golden, buf.Bytes(), 0644))
+	        }
+	        expected, err := os.ReadFile(golden)
+	        require.NoError(t, err)
+	        require.Equal(t, string(expected), buf.String())
 	    })
 	}
 }
@@ -0,0 +1,140 @@
+package mate
+
+import (
+    "fmt"
+    "sort"
+    "goChess/chess"
+)
+
+var NoMateFoundError = fmt.Errorf("No forced mate found")
+
+// Solution is a forced mate: the attacker plays Move and every defence is
+// answered by a mating continuation. A Solution without defences mates
+// immediately.
+type Solution struct {
+    Move     chess.Move
+    Defences []Defence
+}
+
+type Defence struct {
+    Move  chess.Move
+    Reply *Solution
+}
+
+type solver struct {
+    attacker chess.PlayerType
+    // positions known not to be mates in the given number of moves
+    refuted  map[uint64]int
+}
+
+// Solve looks for the shortest forced mate of at most n moves for the side
+// to move in fen, with a depth limited AND/OR search over all legal moves.
+func Solve(fen string, n int) (*Solution, error) {
+    board, toMove, err := chess.ParseFEN(fen)
+    if err != nil {
+        return nil, err
+    }
+
+    return SolveBoard(board, toMove, n)
+}
+
+func SolveBoard(board *chess.Board, attacker chess.PlayerType, n int) (*Solution, error) {
+    s := &solver{attacker: attacker, refuted: make(map[uint64]int)}
+    for depth := 1; depth <= n; depth++ {
+        if sol := s.attack(board, depth); sol != nil {
+            return sol, nil
+        }
+    }
+
+    return nil, NoMateFoundError
+}
+
+// Length returns the number of attacker moves of the longest line.
+func (s *Solution) Length() int {
+    longest := 0
+    for _, d := range s.Defences {
+        if l := d.Reply.Length(); l > longest {
+            longest = l
+        }
+    }
+
+    return longest + 1
+}
+
+// attack is the OR node: one attacker move has to mate against every
+// defence within n moves.
+func (s *solver) attack(board *chess.Board, n int) *Solution {
+    key := board.Hash(s.attacker)
+    if refuted, ok := s.refuted[key]; ok && refuted >= n {
+        return nil
+    }
+
+    defender := chess.Opponent(s.attacker)
+    for _, m := range s.candidates(board) {
+        nb, err := board.MakeMove(m)
+        if err != nil {
+            continue
+        }
+
+        if nb.IsCheckmate(defender) {
+            return &Solution{Move: m}
+        }
+        if n == 1 {
+            continue
+        }
+
+        if defences, ok := s.defend(nb, n); ok {
+            return &Solution{Move: m, Defences: defences}
+        }
+    }
+
+    s.refuted[key] = n
+    return nil
+}
+
+// defend is the AND node: every defence must still lose within n-1 moves.
+func (s *solver) defend(board *chess.Board, n int) ([]Defence, bool) {
+    moves := board.LegalMoves(chess.Opponent(s.attacker))
+    if len(moves) == 0 {
+        // stalemate
+        return nil, false
+    }
+
+    defences := make([]Defence, 0, len(moves))
+    for _, m := range moves {
+        nb, err := board.MakeMove(m)
+        if err != nil {
+            return nil, false
+        }
+        reply := s.attack(nb, n-1)
+        if reply == nil {
+            return nil, false
+        }
+        defences = append(defences, Defence{Move: m, Reply: reply})
+    }
+
+    return defences, true
+}
+
+// candidates orders the attacker's moves with checks first, then captures,
+// since those are the moves most likely to force mate.
+func (s *solver) candidates(board *chess.Board) []chess.Move {
+    moves := board.LegalMoves(s.attacker)
+    defender := chess.Opponent(s.attacker)
+
+    score := make(map[chess.Move]int, len(moves))
+    for _, m := range moves {
+        if nb, err := board.MakeMove(m); err == nil && nb.InCheck(defender) {
+            score[m] += 2
+        }
+        if board.GetPiece(m.To().X(), m.To().Y()).Type() != chess.PieceNone {
+            score[m] += 1
+        }
+    }
+
+    sort.SliceStable(moves, func(i, j int) bool {
+        return score[moves[i]] > score[moves[j]]
+    })
+
+    return moves
+}
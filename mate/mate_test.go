@@ -0,0 +1,93 @@
+package mate
+
+import (
+    "testing"
+    "goChess/chess"
+    "github.com/stretchr/testify/require"
+)
+
+func TestMateInOne(t *testing.T) {
+    sol, err := Solve("6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", 1)
+    require.NoError(t, err)
+    require.Equal(t, chess.NewMove(7, 0, 0, 0), sol.Move)
+    require.Empty(t, sol.Defences)
+    require.Equal(t, 1, sol.Length())
+}
+
+func TestMateInTwo(t *testing.T) {
+    // two rooks roll the king up the board
+    sol, err := Solve("4k3/8/8/8/8/8/R7/1R5K w - - 0 1", 2)
+    require.NoError(t, err)
+    require.Equal(t, 2, sol.Length())
+    require.NotEmpty(t, sol.Defences)
+
+    board, _, err := chess.ParseFEN("4k3/8/8/8/8/8/R7/1R5K w - - 0 1")
+    require.NoError(t, err)
+    requireMates(t, board, sol)
+}
+
+func TestShortestMateFirst(t *testing.T) {
+    sol, err := Solve("6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", 3)
+    require.NoError(t, err)
+    require.Equal(t, 1, sol.Length())
+}
+
+func TestNoMate(t *testing.T) {
+    _, err := Solve("6k1/5ppp/8/8/8/8/8/6K1 w - - 0 1", 2)
+    require.ErrorIs(t, err, NoMateFoundError)
+
+    // Qg6 would leave the king without moves, but that is stalemate
+    _, err = Solve("7k/8/4Q3/8/8/8/8/6K1 w - - 0 1", 1)
+    require.ErrorIs(t, err, NoMateFoundError)
+}
+
+func TestUnderpromotion(t *testing.T) {
+    // a knight on f8 mates where a queen would not even give check
+    sol, err := Solve("6br/5Ppk/7p/8/8/8/8/K7 w - - 0 1", 2)
+    require.NoError(t, err)
+    require.Equal(t, chess.NewPromotion(1, 5, 0, 5, chess.PieceKnight), sol.Move)
+    require.Equal(t, 1, sol.Length())
+
+    // Qf8 threatens mate against everything but f1=N+, which forks the
+    // king and the queen
+    board, _, err := chess.ParseFEN("Q7/8/8/4k3/8/4K3/5p2/3Q4 w - - 0 1")
+    require.NoError(t, err)
+    _, err = SolveBoard(board, chess.PlayerWhite, 2)
+    require.ErrorIs(t, err, NoMateFoundError)
+
+    nb, err := board.MakeMove(chess.NewMove(0, 0, 0, 5))
+    require.NoError(t, err)
+    for _, m := range nb.LegalMoves(chess.PlayerBlack) {
+        after, err := nb.MakeMove(m)
+        require.NoError(t, err)
+        _, err = SolveBoard(after, chess.PlayerWhite, 1)
+        if m == chess.NewPromotion(6, 5, 7, 5, chess.PieceKnight) {
+            require.ErrorIs(t, err, NoMateFoundError)
+        } else {
+            require.NoError(t, err, nb.UCI(m))
+        }
+    }
+}
+
+func TestInvalidFEN(t *testing.T) {
+    _, err := Solve("not a fen", 1)
+    require.ErrorIs(t, err, chess.InvalidFENError)
+}
+
+// requireMates walks the whole solution tree and checks that every
+// defence is covered and every leaf is a checkmate.
+func requireMates(t *testing.T, board *chess.Board, sol *Solution) {
+    nb, err := board.MakeMove(sol.Move)
+    require.NoError(t, err)
+    if len(sol.Defences) == 0 {
+        require.True(t, nb.IsCheckmate(chess.PlayerBlack))
+        return
+    }
+
+    require.Len(t, sol.Defences, len(nb.LegalMoves(chess.PlayerBlack)))
+    for _, d := range sol.Defences {
+        after, err := nb.MakeMove(d.Move)
+        require.NoError(t, err)
+        requireMates(t, after, d.Reply)
+    }
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"goChess/book"
+	"goChess/chess"
+	"goChess/engine"
+	"goChess/printer"
+)
+
+// player plays a game of standard chess against the engine: book moves
+// while the book has some, searched moves after that.
+type player struct {
+	in     *bufio.Scanner
+	out    io.Writer
+	book   *book.Book
+	engine *engine.Engine
+	depth  int
+	rand   *rand.Rand
+	human  chess.PlayerType
+}
+
+// play runs the game until it is over or the input ends.
+func (p *player) play() error {
+	g, err := chess.NewGame(chess.Standard{})
+	if err != nil {
+		return err
+	}
+	if err := p.show(g); err != nil {
+		return err
+	}
+
+	for {
+		if o, over := g.Outcome(); over {
+			fmt.Fprintf(p.out, "%v, winner: %v\n", o.Reason, o.Winner)
+			return nil
+		}
+
+		var m chess.Move
+		if g.ToMove() == p.human {
+			fmt.Fprint(p.out, "Your move: ")
+			if !p.in.Scan() {
+				return p.in.Err()
+			}
+			m, err = readMove(g, p.in.Text())
+			if err != nil {
+				fmt.Fprintln(p.out, err)
+				continue
+			}
+		} else {
+			var from string
+			m, from = p.engineMove(g)
+			fmt.Fprintf(p.out, "goChess plays %v (%v)\n", g.Board().UCI(m), from)
+		}
+
+		if err := g.Play(m); err != nil {
+			return err
+		}
+		if err := p.show(g); err != nil {
+			return err
+		}
+	}
+}
+
+// engineMove picks a book move at random by weight, or searches if the
+// book has none. It also says where the move came from.
+func (p *player) engineMove(g *chess.Game) (chess.Move, string) {
+	last := chess.Move{}
+	if moves := g.Moves(); len(moves) > 0 {
+		last = moves[len(moves)-1]
+	}
+	if p.book != nil {
+		if m, ok := p.book.Pick(g.Board(), g.ToMove(), last, p.rand); ok {
+			return m, "book"
+		}
+	}
+
+	r := p.engine.Search(context.Background(), g.Board(), g.ToMove(), engine.Limits{Depth: p.depth})
+	return r.Move, "search"
+}
+
+func (p *player) show(g *chess.Game) error {
+	sel := g.Board().SelectNone()
+	return printer.TextRenderer{}.Render(p.out, printer.NewView(&sel))
+}
+
+// readMove reads a move of the player to move in SAN, such as "Nf3", or in
+// UCI notation, such as "g1f3".
+func readMove(g *chess.Game, s string) (chess.Move, error) {
+	s = strings.TrimSpace(s)
+	for _, m := range g.LegalMoves() {
+		if g.Board().UCI(m) == s {
+			return m, nil
+		}
+	}
+	return g.Board().ParseSAN(s, g.ToMove())
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+	"goChess/book"
+	"goChess/chess"
+	"goChess/engine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayPicksBookMoves(t *testing.T) {
+	b, _, err := book.BuildFromPGN(strings.NewReader("1. e4 e5 2. Nf3 Nc6 *\n"), book.BuildOptions{})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	p := &player{
+		in: bufio.NewScanner(strings.NewReader("e5\nNc6\nKd7\nb8\n")),
+		out: &out,
+		book: b,
+		engine: engine.NewEngine(1),
+		depth: 2,
+		rand: rand.New(rand.NewSource(1)),
+		human: chess.PlayerBlack,
+	}
+	require.NoError(t, p.play())
+
+	// the book has a move for the first two positions, then the engine
+	// searches
+	s := out.String()
+	require.Contains(t, s, "goChess plays e2e4 (book)")
+	require.Contains(t, s, "goChess plays g1f3 (book)")
+	require.Contains(t, s, "(search)")
+	require.Equal(t, 3, strings.Count(s, "goChess plays"))
+	// the king can't step onto its own pawn, and b8 is no move
+	require.Equal(t, 2, strings.Count(s, "Invalid SAN"))
+}
+
+func TestPlayWithoutBook(t *testing.T) {
+	var out bytes.Buffer
+	p := &player{
+		in: bufio.NewScanner(strings.NewReader("")),
+		out: &out,
+		engine: engine.NewEngine(1),
+		depth: 2,
+		rand: rand.New(rand.NewSource(1)),
+		human: chess.PlayerBlack,
+	}
+	require.NoError(t, p.play())
+	require.Contains(t, out.String(), "(search)")
+}
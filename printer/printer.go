@@ -1,7 +1,6 @@
 package printer
 
 import (
-    "fmt"
     "goChess/chess"
     "github.com/fatih/color"
 )
@@ -16,13 +15,15 @@ var ThreatenedColor  = color.BgRGB(255, 102, 153)
 var PossibleColor    = color.BgRGB(102, 204, 255)
 var CheckColor       = color.BgRGB(255, 77, 77)
 
-type printUnit struct {
-    piece        chess.Piece
-    light        bool
-    selected     bool
-    threatened   bool
-    possibleMove bool
-    inCheck      bool
+// Square is what a renderer draws on one square of the board: the piece
+// and the highlights that apply to it.
+type Square struct {
+    Piece        chess.Piece
+    Light        bool
+    Selected     bool
+    Threatened   bool
+    PossibleMove bool
+    InCheck      bool
 }
 
 func ChessPieceToString(piece chess.Piece) string {
@@ -39,84 +40,76 @@ func ChessPieceToString(piece chess.Piece) string {
         return "B"
     case chess.PiecePawn:
         return "P"
+    case chess.PieceArchbishop:
+        return "A"
+    case chess.PieceChancellor:
+        return "C"
     default:
         return " "
     }
 }
 
-func PrintChessBoard(board *chess.Board) {
+func PrintChessBoard(board *chess.Board) error {
     var sel chess.Select = board.SelectNone()
-    PrintSelect(&sel)
+    return PrintSelect(&sel)
 }
 
-func makePrintUnitsMap(sel *chess.Select) [][]printUnit {
+func makeSquaresMap(sel *chess.Select) [][]Square {
     board := sel.Board()
-    pu := make([][]printUnit, board.Size())
+    sq := make([][]Square, board.Height())
 
-    for i := 0; i < board.Size(); i++ {
-        pu[i] = make([]printUnit, board.Size())
-        for j := 0; j < board.Size(); j++ {
+    for i := 0; i < board.Height(); i++ {
+        sq[i] = make([]Square, board.Width())
+        for j := 0; j < board.Width(); j++ {
             piece := board.GetPiece(i, j)
-            pu[i][j] = printUnit {
-                piece: piece,
-                light: (i+j) % 2 != 0,
+            sq[i][j] = Square {
+                Piece: piece,
+                Light: (i+j) % 2 == 0,
             }
 
-            pu[i][j].inCheck = sel.Checking() && piece.Type() == chess.PieceKing && piece.Player() != sel.Piece().Player()
+            sq[i][j].InCheck = sel.Checking() && piece.Type() == chess.PieceKing && piece.Player() != sel.Piece().Player()
         }
     }
 
     if sel.Selected().X() >= 0 {
-        pu[sel.Selected().X()][sel.Selected().Y()].selected = true
+        sq[sel.Selected().X()][sel.Selected().Y()].Selected = true
 
         for _, v := range sel.ThreatenPieces() {
-            pu[v.X()][v.Y()].threatened = true
+            sq[v.X()][v.Y()].Threatened = true
         }
 
         for _, v := range sel.PossibleMoves() {
-            pu[v.X()][v.Y()].possibleMove = true
+            sq[v.X()][v.Y()].PossibleMove = true
         }
     }
 
-    return pu
+    return sq
 }
 
-func (pu printUnit) format() *color.Color {
-    if pu.inCheck {
+func (sq Square) format() *color.Color {
+    if sq.InCheck {
         return CheckColor
     }
 
-    if pu.threatened {
+    if sq.Threatened {
         return ThreatenedColor
     }
 
-    if pu.possibleMove {
+    if sq.PossibleMove {
         return PossibleColor
     }
 
-    if pu.selected {
+    if sq.Selected {
         return SelectedColor
     }
 
-    if pu.light {
+    if sq.Light {
         return WhiteSquareColor
     }
 
     return BlackSquareColor
 }
 
-func PrintSelect(sel *chess.Select) {
-    pu := makePrintUnitsMap(sel)
-    for _, row := range pu {
-        for _, v := range row {
-            f := v.format()
-            if v.piece.Player() == chess.PlayerBlack {
-                f = f.AddRGB(BlackPlayerColor[0], BlackPlayerColor[1], BlackPlayerColor[2])
-            } else {
-                f = f.AddRGB(WhitePlayerColor[0], WhitePlayerColor[1], WhitePlayerColor[2])
-            }
-            f.Printf("\033[1m %v \033[0m", ChessPieceToString(v.piece))
-        }
-        fmt.Println()
-    }
+func PrintSelect(sel *chess.Select) error {
+    return ANSIRenderer{}.Render(color.Output, NewView(sel))
 }
@@ -0,0 +1,117 @@
+package printer
+
+import (
+    "bytes"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "testing"
+    "goChess/chess"
+    "github.com/fatih/color"
+    "github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func requireGolden(t *testing.T, name string, actual []byte) {
+    golden := filepath.Join("testdata", name+".golden")
+    if *update {
+        require.NoError(t, os.WriteFile(golden, actual, 0644))
+    }
+    expected, err := os.ReadFile(golden)
+    require.NoError(t, err)
+    require.Equal(t, string(expected), string(actual))
+}
+
+func checkingView(t *testing.T) View {
+    board := chess.NewChessBoard()
+    board.SetPiece(2, 6, chess.NewPiece(chess.PieceRook, chess.PlayerBlack))
+    board.SetPiece(2, 2, chess.NewPiece(chess.PieceKing, chess.PlayerWhite))
+    board.SetPiece(5, 6, chess.NewPiece(chess.PiecePawn, chess.PlayerWhite))
+    sel, err := board.SelectPiece(2, 6)
+    require.NoError(t, err)
+    return NewView(&sel)
+}
+
+func TestRenderers(t *testing.T) {
+    noColor := color.NoColor
+    color.NoColor = false
+    defer func() { color.NoColor = noColor }()
+
+    renderers := map[string]Renderer{
+        "ansi": ANSIRenderer{},
+        "text": TextRenderer{},
+        "html": HTMLRenderer{},
+    }
+
+    for name, r := range renderers {
+        t.Run(name, func(t *testing.T) {
+            var buf bytes.Buffer
+            require.NoError(t, r.Render(&buf, checkingView(t)))
+            requireGolden(t, "checking_"+name, buf.Bytes())
+        })
+    }
+}
+
+func TestTextRendererStartingPos(t *testing.T) {
+    board := chess.NewChessBoard()
+    board.SetStartingPos()
+
+    var buf bytes.Buffer
+    require.NoError(t, TextRenderer{}.Render(&buf, NewBoardView(board)))
+    requireGolden(t, "starting_pos_text", buf.Bytes())
+}
+
+func TestTextRendererPockets(t *testing.T) {
+    g, err := chess.NewGameFromFEN(chess.Crazyhouse{}, "4k3/8/8/8/8/8/8/4K3[QNpp] w - - 0 1")
+    require.NoError(t, err)
+
+    var buf bytes.Buffer
+    require.NoError(t, TextRenderer{}.Render(&buf, NewBoardView(g.Board())))
+    requireGolden(t, "pockets_text", buf.Bytes())
+}
+
+func TestTextRendererMinichess(t *testing.T) {
+    g, err := chess.NewGame(chess.Gardner{})
+    require.NoError(t, err)
+
+    var buf bytes.Buffer
+    require.NoError(t, TextRenderer{}.Render(&buf, NewBoardView(g.Board())))
+    requireGolden(t, "gardner_text", buf.Bytes())
+}
+
+// countRenderer uses only the exported View accessors, as a renderer
+// outside of this package would.
+type countRenderer struct{}
+
+func (countRenderer) Render(w io.Writer, view View) error {
+    pieces, marked := 0, 0
+    for i := 0; i < view.Height(); i++ {
+        for j := 0; j < view.Width(); j++ {
+            sq := view.Square(i, j)
+            if sq.Piece.Type() != chess.PieceNone {
+                pieces++
+            }
+            if sq.Selected || sq.Threatened || sq.PossibleMove || sq.InCheck {
+                marked++
+            }
+        }
+    }
+    _, err := fmt.Fprintf(w, "%d %d %d", pieces, marked, len(view.Pocket(chess.PlayerBlack)))
+    return err
+}
+
+func TestViewAccessors(t *testing.T) {
+    view := checkingView(t)
+    require.Equal(t, 8, view.Height())
+    require.Equal(t, 8, view.Width())
+    require.True(t, view.Square(2, 6).Selected)
+    require.True(t, view.Square(2, 2).InCheck)
+    require.Empty(t, view.Pocket(chess.PlayerWhite))
+
+    var buf bytes.Buffer
+    require.NoError(t, countRenderer{}.Render(&buf, view))
+    require.Equal(t, "3 11 0", buf.String())
+}
@@ -0,0 +1,204 @@
+package printer
+
+import (
+    "bufio"
+    "fmt"
+    "html"
+    "io"
+    "strings"
+    "goChess/chess"
+    "github.com/fatih/color"
+)
+
+// View is the renderer independent description of a board: the pieces and
+// the highlights of every square, and the pockets of black and white in
+// variants with drops.
+type View struct {
+    squares [][]Square
+    pockets [2][]chess.Piece
+}
+
+type Renderer interface {
+    Render(w io.Writer, view View) error
+}
+
+// ANSIRenderer draws the board with terminal colors, as PrintSelect does.
+type ANSIRenderer struct{}
+
+// TextRenderer draws the board with plain characters only. White pieces are
+// upper case and black pieces lower case. Highlights are marked around the
+// piece: [ ] selected, * possible move, ( ) threatened and # # check.
+type TextRenderer struct{}
+
+// HTMLRenderer draws the board as an HTML table with inline styles.
+type HTMLRenderer struct{}
+
+func NewView(sel *chess.Select) View {
+    board := sel.Board()
+    return View{
+        squares: makeSquaresMap(sel),
+        pockets: [2][]chess.Piece{board.Pocket(chess.PlayerBlack), board.Pocket(chess.PlayerWhite)},
+    }
+}
+
+func NewBoardView(board *chess.Board) View {
+    sel := board.SelectNone()
+    return NewView(&sel)
+}
+
+// Height returns the number of rows of the board.
+func (v View) Height() int {
+    return len(v.squares)
+}
+
+// Width returns the number of columns of the board.
+func (v View) Width() int {
+    if len(v.squares) == 0 {
+        return 0
+    }
+    return len(v.squares[0])
+}
+
+// Square returns what is drawn on square (x, y), with x counting the rows
+// from the top of the board as in chess.Board.
+func (v View) Square(x, y int) Square {
+    return v.squares[x][y]
+}
+
+// Pocket returns the pieces player holds to drop. It is empty outside of
+// the variants with drops.
+func (v View) Pocket(player chess.PlayerType) []chess.Piece {
+    if player == chess.PlayerBlack {
+        return v.pockets[0]
+    }
+    return v.pockets[1]
+}
+
+func (r ANSIRenderer) Render(w io.Writer, view View) error {
+    bw := bufio.NewWriter(w)
+    pocket := func(pieces []chess.Piece) {
+        if len(pieces) == 0 {
+            return
+        }
+        for _, p := range pieces {
+            pieceColor(p).Fprintf(bw, " %v ", ChessPieceToString(p))
+        }
+        fmt.Fprintln(bw)
+    }
+
+    pocket(view.Pocket(chess.PlayerBlack))
+    for _, row := range view.squares {
+        for _, v := range row {
+            v.format().Fprint(bw, pieceColor(v.Piece).Sprintf(" %v ", ChessPieceToString(v.Piece)))
+        }
+        fmt.Fprintln(bw)
+    }
+    pocket(view.Pocket(chess.PlayerWhite))
+    return bw.Flush()
+}
+
+func pieceColor(p chess.Piece) *color.Color {
+    fg := color.RGB(WhitePlayerColor[0], WhitePlayerColor[1], WhitePlayerColor[2])
+    if p.Player() == chess.PlayerBlack {
+        fg = color.RGB(BlackPlayerColor[0], BlackPlayerColor[1], BlackPlayerColor[2])
+    }
+    return fg.Add(color.Bold)
+}
+
+// Render writes the pockets, when not empty, as a line of pieces above the
+// board for black and below it for white.
+func (r TextRenderer) Render(w io.Writer, view View) error {
+    bw := bufio.NewWriter(w)
+    pocket := func(pieces []chess.Piece) {
+        if len(pieces) == 0 {
+            return
+        }
+        for _, p := range pieces {
+            bw.WriteString(Square{Piece: p}.text())
+        }
+        bw.WriteByte('\n')
+    }
+
+    pocket(view.Pocket(chess.PlayerBlack))
+    for _, row := range view.squares {
+        for _, v := range row {
+            bw.WriteString(v.text())
+        }
+        bw.WriteByte('\n')
+    }
+    pocket(view.Pocket(chess.PlayerWhite))
+    return bw.Flush()
+}
+
+func (sq Square) text() string {
+    p := "."
+    if sq.Piece.Type() != chess.PieceNone {
+        p = ChessPieceToString(sq.Piece)
+        if sq.Piece.Player() == chess.PlayerBlack {
+            p = strings.ToLower(p)
+        }
+    }
+
+    if sq.InCheck {
+        return "#" + p + "#"
+    }
+
+    if sq.Threatened {
+        return "(" + p + ")"
+    }
+
+    if sq.PossibleMove {
+        return " * "
+    }
+
+    if sq.Selected {
+        return "[" + p + "]"
+    }
+
+    return " " + p + " "
+}
+
+func (r HTMLRenderer) Render(w io.Writer, view View) error {
+    background := map[*color.Color]string{
+        BlackSquareColor: "#663300",
+        WhiteSquareColor: "#ffcc66",
+        SelectedColor:    "#33cccc",
+        ThreatenedColor:  "#ff6699",
+        PossibleColor:    "#66ccff",
+        CheckColor:       "#ff4d4d",
+    }
+
+    bw := bufio.NewWriter(w)
+    pocket := func(pieces []chess.Piece) {
+        if len(pieces) == 0 {
+            return
+        }
+        fmt.Fprint(bw, `<div class="chess-pocket" style="font-weight: bold;">`)
+        for _, p := range pieces {
+            fg := WhitePlayerColor
+            if p.Player() == chess.PlayerBlack {
+                fg = BlackPlayerColor
+            }
+            fmt.Fprintf(bw, `<span style="color: #%02x%02x%02x;">%s</span>`, fg[0], fg[1], fg[2], html.EscapeString(ChessPieceToString(p)))
+        }
+        fmt.Fprintln(bw, "</div>")
+    }
+
+    pocket(view.Pocket(chess.PlayerBlack))
+    fmt.Fprintln(bw, `<table class="chess-board" style="border-collapse: collapse;">`)
+    for _, row := range view.squares {
+        fmt.Fprint(bw, "<tr>")
+        for _, v := range row {
+            fg := WhitePlayerColor
+            if v.Piece.Player() == chess.PlayerBlack {
+                fg = BlackPlayerColor
+            }
+            fmt.Fprintf(bw, `<td style="width: 2em; height: 2em; text-align: center; font-weight: bold; background: %s; color: #%02x%02x%02x;">%s</td>`,
+                background[v.format()], fg[0], fg[1], fg[2], html.EscapeString(strings.TrimSpace(ChessPieceToString(v.Piece))))
+        }
+        fmt.Fprintln(bw, "</tr>")
+    }
+    fmt.Fprintln(bw, "</table>")
+    pocket(view.Pocket(chess.PlayerWhite))
+    return bw.Flush()
+}
@@ -0,0 +1,251 @@
+package raster
+
+import (
+    "image"
+    "image/color"
+    "image/draw"
+    "image/gif"
+    "image/png"
+    "io"
+    "time"
+    "goChess/chess"
+    "goChess/printer"
+)
+
+//Color Scheme
+var BlackSquareColor = color.RGBA{102, 51, 0, 255}
+var WhiteSquareColor = color.RGBA{255, 204, 102, 255}
+var BlackPlayerColor = color.RGBA{255, 51, 0, 255}
+var WhitePlayerColor = color.RGBA{204, 255, 255, 255}
+var SelectedColor    = color.RGBA{51, 204, 204, 255}
+var ThreatenedColor  = color.RGBA{255, 102, 153, 255}
+var PossibleColor    = color.RGBA{102, 204, 255, 255}
+var CheckColor       = color.RGBA{255, 77, 77, 255}
+var LastMoveColor    = color.RGBA{205, 210, 106, 255}
+var OutlineColor     = color.RGBA{0, 0, 0, 255}
+
+const DefaultSquareSize = 48
+const DefaultFrameDelay = time.Second
+
+type Options struct {
+    // SquareSize is the side of a single square in pixels.
+    SquareSize  int
+    // Orientation is the player sitting at the bottom of the board.
+    Orientation chess.PlayerType
+}
+
+type GIFOptions struct {
+    Options
+    // Delay is how long every position is shown.
+    Delay    time.Duration
+    // LastMove highlights the squares that changed since the previous position.
+    LastMove bool
+}
+
+func DefaultOptions() Options {
+    return Options{
+        SquareSize: DefaultSquareSize,
+        Orientation: chess.PlayerWhite,
+    }
+}
+
+func DefaultGIFOptions() GIFOptions {
+    return GIFOptions{
+        Options: DefaultOptions(),
+        Delay: DefaultFrameDelay,
+        LastMove: true,
+    }
+}
+
+func (opts Options) normalize() Options {
+    if opts.SquareSize <= 0 {
+        opts.SquareSize = DefaultSquareSize
+    }
+    if opts.Orientation == "" || opts.Orientation == chess.PlayerNone {
+        opts.Orientation = chess.PlayerWhite
+    }
+    return opts
+}
+
+func RenderBoard(board *chess.Board, opts Options) *image.RGBA {
+    sel := board.SelectNone()
+    return RenderSelect(&sel, opts)
+}
+
+func RenderSelect(sel *chess.Select, opts Options) *image.RGBA {
+    return RenderView(printer.NewView(sel), opts)
+}
+
+// RenderView draws the squares of view, as the printer renderers do.
+func RenderView(view printer.View, opts Options) *image.RGBA {
+    opts = opts.normalize()
+    img := image.NewRGBA(imageRect(view, opts))
+    drawSquares(img, view, nil, opts)
+    return img
+}
+
+func WritePNG(w io.Writer, board *chess.Board, opts Options) error {
+    return png.Encode(w, RenderBoard(board, opts))
+}
+
+func WriteSelectPNG(w io.Writer, sel *chess.Select, opts Options) error {
+    return png.Encode(w, RenderSelect(sel, opts))
+}
+
+// WriteGIF renders every position as a frame of an animated GIF. The
+// positions are expected in the order they were played, starting with the
+// initial position. The last move is taken to be the squares whose piece
+// changed.
+func WriteGIF(w io.Writer, positions []*chess.Board, opts GIFOptions) error {
+    return writeGIF(w, positions, func(k int, view, prev printer.View) [][]bool {
+        return changedSquares(view, prev)
+    }, opts)
+}
+
+// WriteGameGIF renders every position of g as a frame of an animated GIF,
+// with the squares each move left and reached as the last move.
+func WriteGameGIF(w io.Writer, g *chess.Game, opts GIFOptions) error {
+    moves := g.Moves()
+    return writeGIF(w, g.Positions(), func(k int, view, prev printer.View) [][]bool {
+        marked := make([][]bool, view.Height())
+        for i := range marked {
+            marked[i] = make([]bool, view.Width())
+        }
+        m := moves[k-1]
+        marked[m.From().X()][m.From().Y()] = true
+        marked[m.To().X()][m.To().Y()] = true
+        return marked
+    }, opts)
+}
+
+// writeGIF draws a frame per position. lastMove returns the squares to
+// highlight in frame k, given the view of the position before it.
+func writeGIF(w io.Writer, positions []*chess.Board, lastMove func(k int, view, prev printer.View) [][]bool, opts GIFOptions) error {
+    opts.Options = opts.Options.normalize()
+    delay := int(opts.Delay / (10 * time.Millisecond))
+
+    anim := &gif.GIF{}
+    var prev printer.View
+    for k, board := range positions {
+        view := printer.NewBoardView(board)
+        var marked [][]bool
+        if opts.LastMove && k > 0 {
+            marked = lastMove(k, view, prev)
+        }
+
+        frame := image.NewPaletted(imageRect(view, opts.Options), palette())
+        drawSquares(frame, view, marked, opts.Options)
+
+        anim.Image = append(anim.Image, frame)
+        anim.Delay = append(anim.Delay, delay)
+        prev = view
+    }
+
+    return gif.EncodeAll(w, anim)
+}
+
+// imageRect returns the bounds of the image the squares are drawn on.
+func imageRect(view printer.View, opts Options) image.Rectangle {
+    return image.Rect(0, 0, view.Width()*opts.SquareSize, view.Height()*opts.SquareSize)
+}
+
+func palette() color.Palette {
+    return color.Palette{
+        BlackSquareColor,
+        WhiteSquareColor,
+        BlackPlayerColor,
+        WhitePlayerColor,
+        SelectedColor,
+        ThreatenedColor,
+        PossibleColor,
+        CheckColor,
+        LastMoveColor,
+        OutlineColor,
+    }
+}
+
+// changedSquares marks the squares whose piece differs between view and
+// prev, the squares of the last move.
+func changedSquares(view, prev printer.View) [][]bool {
+    changed := make([][]bool, view.Height())
+    for i := range changed {
+        changed[i] = make([]bool, view.Width())
+        for j := range changed[i] {
+            if i < prev.Height() && j < prev.Width() && view.Square(i, j).Piece != prev.Square(i, j).Piece {
+                changed[i][j] = true
+            }
+        }
+    }
+    return changed
+}
+
+// drawSquares draws every square of view, with the squares marked in
+// lastMove, if any, highlighted.
+func drawSquares(img draw.Image, view printer.View, lastMove [][]bool, opts Options) {
+    for i := 0; i < view.Height(); i++ {
+        for j := 0; j < view.Width(); j++ {
+            x, y := i, j
+            if opts.Orientation == chess.PlayerBlack {
+                x = view.Height() - 1 - i
+                y = view.Width() - 1 - j
+            }
+            sq := view.Square(i, j)
+            rect := image.Rect(y*opts.SquareSize, x*opts.SquareSize, (y+1)*opts.SquareSize, (x+1)*opts.SquareSize)
+            draw.Draw(img, rect, image.NewUniform(fill(sq, lastMove != nil && lastMove[i][j])), image.Point{}, draw.Src)
+            drawPiece(img, rect, sq.Piece)
+        }
+    }
+}
+
+func drawPiece(img draw.Image, rect image.Rectangle, piece chess.Piece) {
+    sprite := pieceSprite(piece)
+    if sprite == nil {
+        return
+    }
+
+    body := WhitePlayerColor
+    if piece.Player() == chess.PlayerBlack {
+        body = BlackPlayerColor
+    }
+
+    side := rect.Dx()
+    for py := 0; py < side; py++ {
+        for px := 0; px < side; px++ {
+            sx := px * spriteSize / side
+            sy := py * spriteSize / side
+            if spriteBody(sprite, sx, sy) {
+                img.Set(rect.Min.X+px, rect.Min.Y+py, body)
+            } else if spriteOutline(sprite, sx, sy) {
+                img.Set(rect.Min.X+px, rect.Min.Y+py, OutlineColor)
+            }
+        }
+    }
+}
+
+func fill(sq printer.Square, lastMove bool) color.RGBA {
+    if sq.InCheck {
+        return CheckColor
+    }
+
+    if sq.Threatened {
+        return ThreatenedColor
+    }
+
+    if sq.PossibleMove {
+        return PossibleColor
+    }
+
+    if sq.Selected {
+        return SelectedColor
+    }
+
+    if lastMove {
+        return LastMoveColor
+    }
+
+    if sq.Light {
+        return WhiteSquareColor
+    }
+
+    return BlackSquareColor
+}
@@ -0,0 +1,106 @@
+package raster
+
+import (
+    "bytes"
+    "image/gif"
+    "image/png"
+    "testing"
+    "time"
+    "goChess/chess"
+    "github.com/stretchr/testify/require"
+)
+
+func TestWritePNG(t *testing.T) {
+    board := chess.NewChessBoard()
+    board.SetStartingPos()
+
+    var buf bytes.Buffer
+    opts := Options{SquareSize: 20}
+    require.NoError(t, WritePNG(&buf, board, opts))
+
+    img, err := png.Decode(&buf)
+    require.NoError(t, err)
+    require.Equal(t, 160, img.Bounds().Dx())
+    require.Equal(t, 160, img.Bounds().Dy())
+
+    // top left corner of a8 and the empty a6 square
+    require.Equal(t, WhiteSquareColor, RenderBoard(board, opts).RGBAAt(0, 0))
+    require.Equal(t, WhiteSquareColor, RenderBoard(board, opts).RGBAAt(10, 50))
+}
+
+func TestRenderWideBoard(t *testing.T) {
+    g, err := chess.NewGame(chess.Capablanca{})
+    require.NoError(t, err)
+    img := RenderBoard(g.Board(), Options{SquareSize: 20})
+    require.Equal(t, 200, img.Bounds().Dx())
+    require.Equal(t, 160, img.Bounds().Dy())
+
+    flipped := RenderBoard(g.Board(), Options{SquareSize: 20, Orientation: chess.PlayerBlack})
+    require.Equal(t, img.RGBAAt(0, 0), flipped.RGBAAt(199, 159))
+}
+
+func TestRenderSelectHighlights(t *testing.T) {
+    board := chess.NewChessBoard()
+    board.SetPiece(2, 6, chess.NewPiece(chess.PieceRook, chess.PlayerBlack))
+    board.SetPiece(2, 2, chess.NewPiece(chess.PiecePawn, chess.PlayerWhite))
+    sel, err := board.SelectPiece(2, 6)
+    require.NoError(t, err)
+
+    img := RenderSelect(&sel, Options{SquareSize: 16})
+    require.Equal(t, SelectedColor, img.RGBAAt(6*16, 2*16))
+    require.Equal(t, ThreatenedColor, img.RGBAAt(2*16, 2*16))
+    require.Equal(t, PossibleColor, img.RGBAAt(6*16, 5*16))
+
+    flipped := RenderSelect(&sel, Options{SquareSize: 16, Orientation: chess.PlayerBlack})
+    require.Equal(t, SelectedColor, flipped.RGBAAt(1*16, 5*16))
+}
+
+func TestWriteGIF(t *testing.T) {
+    first := chess.NewChessBoard()
+    first.SetPiece(6, 4, chess.NewPiece(chess.PiecePawn, chess.PlayerWhite))
+    second := chess.NewChessBoard()
+    second.SetPiece(4, 4, chess.NewPiece(chess.PiecePawn, chess.PlayerWhite))
+
+    var buf bytes.Buffer
+    opts := DefaultGIFOptions()
+    opts.SquareSize = 8
+    opts.Delay = 500 * time.Millisecond
+    require.NoError(t, WriteGIF(&buf, []*chess.Board{first, second}, opts))
+
+    anim, err := gif.DecodeAll(&buf)
+    require.NoError(t, err)
+    require.Len(t, anim.Image, 2)
+    require.Equal(t, []int{50, 50}, anim.Delay)
+
+    r, g, b, _ := anim.Image[1].At(4*8, 6*8).RGBA()
+    lr, lg, lb, _ := LastMoveColor.RGBA()
+    require.Equal(t, []uint32{lr, lg, lb}, []uint32{r, g, b})
+}
+
+func TestWriteGameGIF(t *testing.T) {
+    g, err := chess.NewGame(chess.Standard{})
+    require.NoError(t, err)
+    for _, san := range []string{"e4", "e5", "Nf3"} {
+        m, err := g.Board().ParseSAN(san, g.ToMove())
+        require.NoError(t, err)
+        require.NoError(t, g.Play(m))
+    }
+
+    var buf bytes.Buffer
+    opts := DefaultGIFOptions()
+    opts.SquareSize = 8
+    require.NoError(t, WriteGameGIF(&buf, g, opts))
+
+    anim, err := gif.DecodeAll(&buf)
+    require.NoError(t, err)
+    require.Len(t, anim.Image, 4)
+
+    // g1 and f3 are marked in the last frame, e4 no longer is
+    lr, lg, lb, _ := LastMoveColor.RGBA()
+    for _, sq := range [][2]int{{7, 6}, {5, 5}} {
+        r, g, b, _ := anim.Image[3].At(sq[1]*8, sq[0]*8).RGBA()
+        require.Equal(t, []uint32{lr, lg, lb}, []uint32{r, g, b})
+    }
+    r, gr, b, _ := anim.Image[3].At(4*8, 4*8).RGBA()
+    require.NotEqual(t, []uint32{lr, lg, lb}, []uint32{r, gr, b})
+}
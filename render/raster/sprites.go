@@ -0,0 +1,179 @@
+package raster
+
+import "goChess/chess"
+
+// Piece sprites are drawn on a 16x16 grid, '#' marks the body of the piece.
+// The outline is derived from the body when the sprite is drawn.
+const spriteSize = 16
+
+var pawnSprite = []string{
+    "................",
+    "................",
+    "................",
+    "......####......",
+    ".....######.....",
+    ".....######.....",
+    "......####......",
+    ".....######.....",
+    "......####......",
+    "......####......",
+    ".....######.....",
+    "....########....",
+    "...##########...",
+    "...##########...",
+    "................",
+    "................",
+}
+
+var rookSprite = []string{
+    "................",
+    "................",
+    "...##..##..##...",
+    "...##..##..##...",
+    "...##########...",
+    "....########....",
+    ".....######.....",
+    ".....######.....",
+    ".....######.....",
+    ".....######.....",
+    ".....######.....",
+    "....########....",
+    "...##########...",
+    "...##########...",
+    "................",
+    "................",
+}
+
+var knightSprite = []string{
+    "................",
+    "................",
+    "......#.#.......",
+    ".....######.....",
+    "....########....",
+    "...####.#####...",
+    "...#########....",
+    "....##.######...",
+    ".......######...",
+    "......#######...",
+    ".....########...",
+    "....#########...",
+    "...##########...",
+    "...##########...",
+    "................",
+    "................",
+}
+
+var bishopSprite = []string{
+    "................",
+    ".......##.......",
+    "......####......",
+    ".....###.##.....",
+    ".....##.###.....",
+    ".....######.....",
+    "......####......",
+    "......####......",
+    ".....######.....",
+    "......####......",
+    "......####......",
+    ".....######.....",
+    "...##########...",
+    "...##########...",
+    "................",
+    "................",
+}
+
+var queenSprite = []string{
+    "................",
+    "..#....##....#..",
+    "..##...##...##..",
+    "..###.####.###..",
+    "...##########...",
+    "...##########...",
+    "....########....",
+    "....########....",
+    ".....######.....",
+    ".....######.....",
+    "....########....",
+    "...##########...",
+    "..############..",
+    "..############..",
+    "................",
+    "................",
+}
+
+var kingSprite = []string{
+    ".......##.......",
+    "......####......",
+    ".......##.......",
+    ".....######.....",
+    "....########....",
+    "...##########...",
+    "...##########...",
+    "....########....",
+    ".....######.....",
+    ".....######.....",
+    "....########....",
+    "...##########...",
+    "..############..",
+    "..############..",
+    "................",
+    "................",
+}
+
+// The pieces of Capablanca chess are drawn as the two pieces they combine,
+// split down the middle.
+var archbishopSprite = splitSprite(bishopSprite, knightSprite)
+var chancellorSprite = splitSprite(rookSprite, knightSprite)
+
+// splitSprite joins the left half of left and the right half of right.
+func splitSprite(left, right []string) []string {
+    sprite := make([]string, spriteSize)
+    for y := range sprite {
+        sprite[y] = left[y][:spriteSize/2] + right[y][spriteSize/2:]
+    }
+    return sprite
+}
+
+func pieceSprite(piece chess.Piece) []string {
+    switch t := piece.Type(); t {
+    case chess.PieceKing:
+        return kingSprite
+    case chess.PieceQueen:
+        return queenSprite
+    case chess.PieceKnight:
+        return knightSprite
+    case chess.PieceRook:
+        return rookSprite
+    case chess.PieceBishop:
+        return bishopSprite
+    case chess.PiecePawn:
+        return pawnSprite
+    case chess.PieceArchbishop:
+        return archbishopSprite
+    case chess.PieceChancellor:
+        return chancellorSprite
+    default:
+        return nil
+    }
+}
+
+func spriteBody(sprite []string, x, y int) bool {
+    if x < 0 || y < 0 || y >= len(sprite) || x >= len(sprite[y]) {
+        return false
+    }
+    return sprite[y][x] == '#'
+}
+
+func spriteOutline(sprite []string, x, y int) bool {
+    if spriteBody(sprite, x, y) {
+        return false
+    }
+    for i := -1; i < 2; i++ {
+        for j := -1; j < 2; j++ {
+            if spriteBody(sprite, x+i, y+j) {
+                return true
+            }
+        }
+    }
+    return false
+}
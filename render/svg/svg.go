@@ -0,0 +1,208 @@
+package svg
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "goChess/chess"
+    "goChess/printer"
+)
+
+//Color Scheme
+var BlackSquareColor = "#663300"
+var WhiteSquareColor = "#ffcc66"
+var BlackPlayerColor = "#ff3300"
+var WhitePlayerColor = "#ccffff"
+var SelectedColor    = "#33cccc"
+var ThreatenedColor  = "#ff6699"
+var PossibleColor    = "#66ccff"
+var CheckColor       = "#ff4d4d"
+var ArrowColor       = "#15781b"
+var CoordinateColor  = "#333333"
+
+const DefaultSquareSize = 45
+
+type Arrow struct {
+    FromX int
+    FromY int
+    ToX   int
+    ToY   int
+    Color string
+}
+
+type Options struct {
+    // SquareSize is the side of a single square in pixels.
+    SquareSize  int
+    // Coordinates draws file letters and rank numbers around the board.
+    Coordinates bool
+    // Orientation is the player sitting at the bottom of the board.
+    Orientation chess.PlayerType
+    Arrows      []Arrow
+}
+
+func DefaultOptions() Options {
+    return Options{
+        SquareSize: DefaultSquareSize,
+        Coordinates: true,
+        Orientation: chess.PlayerWhite,
+    }
+}
+
+func RenderBoard(w io.Writer, board *chess.Board, opts Options) error {
+    sel := board.SelectNone()
+    return RenderSelect(w, &sel, opts)
+}
+
+func RenderSelect(w io.Writer, sel *chess.Select, opts Options) error {
+    return RenderView(w, printer.NewView(sel), opts)
+}
+
+// RenderView draws the squares of view, as the printer renderers do.
+func RenderView(w io.Writer, view printer.View, opts Options) error {
+    if opts.SquareSize <= 0 {
+        opts.SquareSize = DefaultSquareSize
+    }
+    if opts.Orientation == "" || opts.Orientation == chess.PlayerNone {
+        opts.Orientation = chess.PlayerWhite
+    }
+
+    size := boardSize{rows: view.Height(), cols: view.Width()}
+    margin := 0
+    if opts.Coordinates {
+        margin = opts.SquareSize / 2
+    }
+    fullWidth := size.cols*opts.SquareSize + 2*margin
+    fullHeight := size.rows*opts.SquareSize + 2*margin
+
+    var buf bytes.Buffer
+    fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", fullWidth, fullHeight, fullWidth, fullHeight)
+    fmt.Fprint(&buf, "<defs>\n")
+    fmt.Fprintf(&buf, `<marker id="arrowhead" viewBox="0 0 10 10" refX="5" refY="5" markerWidth="4" markerHeight="4" orient="auto"><path d="M 0 0 L 10 5 L 0 10 z" fill="context-stroke"/></marker>`+"\n")
+    fmt.Fprint(&buf, "</defs>\n")
+
+    for i := 0; i < size.rows; i++ {
+        for j := 0; j < size.cols; j++ {
+            px, py := opts.position(i, j, size, margin)
+            fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", px, py, opts.SquareSize, opts.SquareSize, fill(view.Square(i, j)))
+        }
+    }
+
+    for i := 0; i < size.rows; i++ {
+        for j := 0; j < size.cols; j++ {
+            piece := view.Square(i, j).Piece
+            if piece.Type() == chess.PieceNone {
+                continue
+            }
+            px, py := opts.position(i, j, size, margin)
+            fg := WhitePlayerColor
+            if piece.Player() == chess.PlayerBlack {
+                fg = BlackPlayerColor
+            }
+            fmt.Fprintf(&buf, `<text x="%d" y="%d" font-size="%d" text-anchor="middle" dominant-baseline="central" fill="%s" stroke="#000000" stroke-width="1">%s</text>`+"\n",
+                px+opts.SquareSize/2, py+opts.SquareSize/2, opts.SquareSize*4/5, fg, pieceGlyph(piece))
+        }
+    }
+
+    if opts.Coordinates {
+        writeCoordinates(&buf, opts, size, margin)
+    }
+
+    for _, a := range opts.Arrows {
+        color := a.Color
+        if color == "" {
+            color = ArrowColor
+        }
+        fx, fy := opts.position(a.FromX, a.FromY, size, margin)
+        tx, ty := opts.position(a.ToX, a.ToY, size, margin)
+        half := opts.SquareSize / 2
+        fmt.Fprintf(&buf, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="%d" stroke-opacity="0.8" stroke-linecap="round" marker-end="url(#arrowhead)"/>`+"\n",
+            fx+half, fy+half, tx+half, ty+half, color, opts.SquareSize/6)
+    }
+
+    fmt.Fprint(&buf, "</svg>\n")
+
+    _, err := w.Write(buf.Bytes())
+    return err
+}
+
+// boardSize is the number of rows and columns of the board drawn.
+type boardSize struct {
+    rows, cols int
+}
+
+// position returns the top left corner of square (x, y) as drawn for the
+// configured orientation.
+func (opts Options) position(x, y int, size boardSize, margin int) (int, int) {
+    if opts.Orientation == chess.PlayerBlack {
+        x = size.rows - 1 - x
+        y = size.cols - 1 - y
+    }
+    return margin + y*opts.SquareSize, margin + x*opts.SquareSize
+}
+
+func writeCoordinates(buf *bytes.Buffer, opts Options, size boardSize, margin int) {
+    fontSize := opts.SquareSize / 3
+    for j := 0; j < size.cols; j++ {
+        px, _ := opts.position(0, j, size, margin)
+        file := string(rune('a' + j))
+        fmt.Fprintf(buf, `<text x="%d" y="%d" font-size="%d" text-anchor="middle" dominant-baseline="central" fill="%s">%s</text>`+"\n",
+            px+opts.SquareSize/2, margin/2, fontSize, CoordinateColor, file)
+        fmt.Fprintf(buf, `<text x="%d" y="%d" font-size="%d" text-anchor="middle" dominant-baseline="central" fill="%s">%s</text>`+"\n",
+            px+opts.SquareSize/2, margin+size.rows*opts.SquareSize+margin/2, fontSize, CoordinateColor, file)
+    }
+    for i := 0; i < size.rows; i++ {
+        _, py := opts.position(i, 0, size, margin)
+        rank := fmt.Sprint(size.rows - i)
+        fmt.Fprintf(buf, `<text x="%d" y="%d" font-size="%d" text-anchor="middle" dominant-baseline="central" fill="%s">%s</text>`+"\n",
+            margin/2, py+opts.SquareSize/2, fontSize, CoordinateColor, rank)
+        fmt.Fprintf(buf, `<text x="%d" y="%d" font-size="%d" text-anchor="middle" dominant-baseline="central" fill="%s">%s</text>`+"\n",
+            margin+size.cols*opts.SquareSize+margin/2, py+opts.SquareSize/2, fontSize, CoordinateColor, rank)
+    }
+}
+
+func pieceGlyph(piece chess.Piece) string {
+    switch t := piece.Type(); t {
+    case chess.PieceKing:
+        return "&#9818;"
+    case chess.PieceQueen:
+        return "&#9819;"
+    case chess.PieceRook:
+        return "&#9820;"
+    case chess.PieceBishop:
+        return "&#9821;"
+    case chess.PieceKnight:
+        return "&#9822;"
+    case chess.PiecePawn:
+        return "&#9823;"
+    case chess.PieceArchbishop:
+        return "A"
+    case chess.PieceChancellor:
+        return "C"
+    default:
+        return ""
+    }
+}
+
+func fill(sq printer.Square) string {
+    if sq.InCheck {
+        return CheckColor
+    }
+
+    if sq.Threatened {
+        return ThreatenedColor
+    }
+
+    if sq.PossibleMove {
+        return PossibleColor
+    }
+
+    if sq.Selected {
+        return SelectedColor
+    }
+
+    if sq.Light {
+        return WhiteSquareColor
+    }
+
+    return BlackSquareColor
+}
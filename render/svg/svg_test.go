@@ -0,0 +1,95 @@
+package svg
+
+import (
+    "bytes"
+    "encoding/xml"
+    "io"
+    "strings"
+    "testing"
+    "goChess/chess"
+    "goChess/printer"
+    "github.com/stretchr/testify/require"
+)
+
+func requireWellFormed(t *testing.T, data []byte) {
+    dec := xml.NewDecoder(bytes.NewReader(data))
+    for {
+        _, err := dec.Token()
+        if err == io.EOF {
+            return
+        }
+        require.NoError(t, err)
+    }
+}
+
+func TestRenderBoard(t *testing.T) {
+    board := chess.NewChessBoard()
+    board.SetStartingPos()
+
+    var buf bytes.Buffer
+    require.NoError(t, RenderBoard(&buf, board, DefaultOptions()))
+    requireWellFormed(t, buf.Bytes())
+
+    out := buf.String()
+    require.Equal(t, 64, strings.Count(out, "<rect"))
+    require.Equal(t, 32, strings.Count(out, "stroke=\"#000000\""))
+    require.Contains(t, out, ">a</text>")
+    require.Contains(t, out, ">8</text>")
+}
+
+func TestRenderSelect(t *testing.T) {
+    board := chess.NewChessBoard()
+    board.SetPiece(2, 6, chess.NewPiece(chess.PieceRook, chess.PlayerBlack))
+    board.SetPiece(2, 2, chess.NewPiece(chess.PiecePawn, chess.PlayerWhite))
+    sel, err := board.SelectPiece(2, 6)
+    require.NoError(t, err)
+
+    var buf bytes.Buffer
+    opts := DefaultOptions()
+    opts.Arrows = []Arrow{{FromX: 2, FromY: 6, ToX: 2, ToY: 2}}
+    require.NoError(t, RenderSelect(&buf, &sel, opts))
+    requireWellFormed(t, buf.Bytes())
+
+    out := buf.String()
+    require.Equal(t, 1, strings.Count(out, "fill=\""+SelectedColor+"\""))
+    require.Equal(t, 1, strings.Count(out, "fill=\""+ThreatenedColor+"\""))
+    require.Equal(t, len(sel.PossibleMoves())-1, strings.Count(out, "fill=\""+PossibleColor+"\""))
+    require.Equal(t, 1, strings.Count(out, "<line"))
+}
+
+func TestRenderCheck(t *testing.T) {
+    board := chess.NewChessBoard()
+    board.SetPiece(2, 6, chess.NewPiece(chess.PieceRook, chess.PlayerBlack))
+    board.SetPiece(2, 2, chess.NewPiece(chess.PieceKing, chess.PlayerWhite))
+    sel, err := board.SelectPiece(2, 6)
+    require.NoError(t, err)
+
+    var buf bytes.Buffer
+    require.NoError(t, RenderSelect(&buf, &sel, DefaultOptions()))
+    require.Equal(t, 1, strings.Count(buf.String(), "fill=\""+CheckColor+"\""))
+}
+
+func TestOrientation(t *testing.T) {
+    opts := Options{SquareSize: 10, Orientation: chess.PlayerWhite}
+    x, y := opts.position(0, 0, boardSize{rows: 8, cols: 8}, 0)
+    require.Equal(t, 0, x)
+    require.Equal(t, 0, y)
+
+    opts.Orientation = chess.PlayerBlack
+    x, y = opts.position(0, 0, boardSize{rows: 8, cols: 8}, 0)
+    require.Equal(t, 70, x)
+    require.Equal(t, 70, y)
+
+    x, y = opts.position(0, 0, boardSize{rows: 8, cols: 10}, 0)
+    require.Equal(t, 90, x)
+    require.Equal(t, 70, y)
+}
+
+func TestRenderViewLightSquares(t *testing.T) {
+    view := printer.NewBoardView(chess.NewChessBoard())
+    require.True(t, view.Square(0, 0).Light)
+
+    var buf bytes.Buffer
+    require.NoError(t, RenderView(&buf, view, Options{SquareSize: 10}))
+    require.Contains(t, buf.String(), `<rect x="0" y="0" width="10" height="10" fill="`+WhiteSquareColor+`"/>`)
+}
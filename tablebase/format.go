@@ -0,0 +1,96 @@
+package tablebase
+
+import (
+    "bufio"
+    "compress/zlib"
+    "fmt"
+    "io"
+    "os"
+)
+
+var InvalidTableError = fmt.Errorf("Invalid tablebase file")
+var SyzygyTableError = fmt.Errorf("Syzygy tables are not supported")
+
+// A table file starts with the magic, a format version and the length
+// prefixed signature, followed by the zlib compressed table data. Broken
+// entries come in long runs, so most tables shrink to a small fraction of
+// their size.
+const (
+    fileMagic   = "GCTB"
+    fileVersion = 1
+)
+
+// syzygyMagics start the WDL and DTZ files of Syzygy tables, which Read
+// recognizes only to say that it can't read them.
+var syzygyMagics = []string{"\x71\xe8\x23\x5d", "\xd7\x66\x0c\xa5"}
+
+// Open reads a table file written by Table.Write. Syzygy .rtbw and .rtbz
+// files are not read; tables come from Generate.
+func Open(path string) (*Table, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    return Read(f)
+}
+
+func Read(r io.Reader) (*Table, error) {
+    br := bufio.NewReader(r)
+    header := make([]byte, len(fileMagic)+2)
+    if _, err := io.ReadFull(br, header); err != nil {
+        return nil, fmt.Errorf("%w: %v", InvalidTableError, err)
+    }
+    if magic := string(header[:len(fileMagic)]); magic != fileMagic {
+        for _, m := range syzygyMagics {
+            if magic == m {
+                return nil, fmt.Errorf("%w: %w", InvalidTableError, SyzygyTableError)
+            }
+        }
+        return nil, fmt.Errorf("%w: bad magic", InvalidTableError)
+    }
+    if header[len(fileMagic)] != fileVersion {
+        return nil, fmt.Errorf("%w: unknown version %d", InvalidTableError, header[len(fileMagic)])
+    }
+
+    signature := make([]byte, header[len(fileMagic)+1])
+    if _, err := io.ReadFull(br, signature); err != nil {
+        return nil, fmt.Errorf("%w: %v", InvalidTableError, err)
+    }
+    m, err := parseMaterial(string(signature))
+    if err != nil || !m.canonical() {
+        return nil, fmt.Errorf("%w: bad material %q", InvalidTableError, signature)
+    }
+
+    zr, err := zlib.NewReader(br)
+    if err != nil {
+        return nil, fmt.Errorf("%w: %v", InvalidTableError, err)
+    }
+    defer zr.Close()
+
+    data := make([]byte, newLayout(m).size())
+    if _, err := io.ReadFull(zr, data); err != nil {
+        return nil, fmt.Errorf("%w: %v", InvalidTableError, err)
+    }
+
+    return &Table{m: m, data: data}, nil
+}
+
+func (t *Table) Write(w io.Writer) error {
+    signature := t.m.String()
+    header := append([]byte(fileMagic), fileVersion, byte(len(signature)))
+    if _, err := w.Write(append(header, signature...)); err != nil {
+        return err
+    }
+
+    zw, err := zlib.NewWriterLevel(w, zlib.BestCompression)
+    if err != nil {
+        return err
+    }
+    if _, err := zw.Write(t.data); err != nil {
+        return err
+    }
+
+    return zw.Close()
+}
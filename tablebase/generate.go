@@ -0,0 +1,360 @@
+package tablebase
+
+import (
+    "fmt"
+    "goChess/chess"
+)
+
+var MissingTableError = fmt.Errorf("Missing table")
+var TooDeepError = fmt.Errorf("Distance to mate too long to store")
+
+var (
+    rookDirs    = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+    bishopDirs  = [][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+    knightJumps = [][2]int{{1, 2}, {2, 1}, {-1, 2}, {-2, 1}, {1, -2}, {2, -1}, {-1, -2}, {-2, -1}}
+    kingSteps   = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+    promotions  = []chess.PieceType{chess.PieceQueen, chess.PieceRook, chess.PieceBishop, chess.PieceKnight}
+)
+
+// Generate builds the table for signature, along with every smaller table
+// its captures and promotions lead to, and adds them all to the set.
+func (s *Set) Generate(signature string) (*Table, error) {
+    m, err := parseMaterial(signature)
+    if err != nil {
+        return nil, err
+    }
+    return s.generate(m)
+}
+
+func (s *Set) generate(m material) (*Table, error) {
+    if !m.canonical() {
+        m = m.swapped()
+    }
+    if t, ok := s.tables[m.String()]; ok {
+        return t, nil
+    }
+
+    for _, sub := range m.successors() {
+        if sub.count() > 2 {
+            if _, err := s.generate(sub); err != nil {
+                return nil, err
+            }
+        }
+    }
+
+    g := newGenerator(s, m)
+    if err := g.classify(); err != nil {
+        return nil, err
+    }
+    if err := g.propagate(); err != nil {
+        return nil, err
+    }
+
+    t := &Table{m: m, data: g.data}
+    s.Add(t)
+
+    return t, nil
+}
+
+// successors lists the materials a single capture or promotion leads to.
+func (m material) successors() []material {
+    subs := make([]material, 0, m.count())
+    for side, pieces := range [][]chess.PieceType{m.white, m.black} {
+        for i := 1; i < len(pieces); i++ {
+            removed := append(append([]chess.PieceType{}, pieces[:i]...), pieces[i+1:]...)
+            subs = append(subs, m.with(side, removed))
+            if pieces[i] != chess.PiecePawn {
+                continue
+            }
+            for _, t := range promotions {
+                promoted := append([]chess.PieceType{}, pieces...)
+                promoted[i] = t
+                sortPieces(promoted)
+                sub := m.with(side, promoted)
+                subs = append(subs, sub)
+                other := sub.black
+                if side == 1 {
+                    other = sub.white
+                }
+                for j := 1; j < len(other); j++ {
+                    captured := append(append([]chess.PieceType{}, other[:j]...), other[j+1:]...)
+                    subs = append(subs, sub.with(1-side, captured))
+                }
+            }
+        }
+    }
+    return subs
+}
+
+func (m material) with(side int, pieces []chess.PieceType) material {
+    if side == 0 {
+        return material{white: pieces, black: m.black}
+    }
+    return material{white: m.white, black: pieces}
+}
+
+// generator holds the state of a retrograde analysis. Positions are first
+// classified one by one; results then spread backwards from the mates, one
+// ply of distance at a time.
+type generator struct {
+    set       *Set
+    l         layout
+    data      []byte
+    done      []bool
+    remaining []uint8
+    escape    []bool
+    exitWin   []uint8
+    queue     [][]int32
+}
+
+func newGenerator(s *Set, m material) *generator {
+    l := newLayout(m)
+    n := l.size()
+    return &generator{
+        set:       s,
+        l:         l,
+        data:      make([]byte, n),
+        done:      make([]bool, n),
+        remaining: make([]uint8, n),
+        escape:    make([]bool, n),
+        exitWin:   make([]uint8, n),
+        queue:     make([][]int32, broken),
+    }
+}
+
+// push schedules idx to be resolved at dtm plies. A position may be pushed
+// more than once; it takes the first, shortest, distance.
+func (g *generator) push(idx, dtm int) error {
+    if dtm >= len(g.queue) {
+        return fmt.Errorf("%w: %v", TooDeepError, g.l.m)
+    }
+    g.queue[dtm] = append(g.queue[dtm], int32(idx))
+    return nil
+}
+
+// classify looks at every position once. Illegal positions are marked as
+// broken, mates and stalemates are settled, and moves that leave the table
+// are looked up in the smaller tables. Moves that stay in the table are
+// counted so that propagate knows when all of them have been refuted.
+func (g *generator) classify() error {
+    squares := make([]int, g.l.m.count())
+    pos := newPosition(&g.l)
+    targets := make([]int, 0, 32)
+    children := make([]int, 0, 64)
+
+    for idx := range g.data {
+        side := g.l.decode(idx, squares)
+        if !g.l.sane(squares) || g.l.index(side, squares) != idx {
+            g.data[idx], g.done[idx] = broken, true
+            continue
+        }
+
+        pos.set(squares)
+        mover := sidePlayer(side)
+        if pos.attacked(pos.squares[pos.king(chess.Opponent(mover))], mover) {
+            g.data[idx], g.done[idx] = broken, true
+            continue
+        }
+
+        legal, bestWin := 0, -1
+        children = children[:0]
+        for i := range pos.squares {
+            if _, player := g.l.m.piece(i); player != mover {
+                continue
+            }
+            from := pos.squares[i]
+            targets = pos.targets(i, targets[:0])
+            for _, to := range targets {
+                taken := pos.move(i, to)
+                if pos.attacked(pos.squares[pos.king(mover)], chess.Opponent(mover)) {
+                    pos.undo(i, from, taken)
+                    continue
+                }
+                legal++
+
+                t, _ := g.l.m.piece(i)
+                promotes := t == chess.PiecePawn && (to/chess.BoardSize == 0 || to/chess.BoardSize == chess.BoardSize-1)
+                if taken < 0 && !promotes {
+                    children = addUnique(children, g.l.index(1-side, pos.squares))
+                    pos.undo(i, from, taken)
+                    continue
+                }
+
+                // a capture or a promotion leaves the table, a promotion
+                // once for every piece
+                pieces := []chess.PieceType{chess.PieceNone}
+                if promotes {
+                    pieces = promotions
+                }
+                for _, promoted := range pieces {
+                    v, ok := g.set.value(pos.board(promoted), chess.Opponent(mover))
+                    if !ok {
+                        return fmt.Errorf("%w: after a move from %d to %d in %v", MissingTableError, from, to, g.l.m)
+                    }
+                    switch res, dtm := decodeValue(v); res {
+                    case Loss:
+                        if bestWin < 0 || dtm+1 < bestWin {
+                            bestWin = dtm + 1
+                        }
+                        g.escape[idx] = true
+                    case Draw:
+                        g.escape[idx] = true
+                    case Win:
+                        if uint8(dtm+1) > g.exitWin[idx] {
+                            g.exitWin[idx] = uint8(dtm + 1)
+                        }
+                    }
+                }
+                pos.undo(i, from, taken)
+            }
+        }
+
+        if legal == 0 {
+            if pos.attacked(pos.squares[pos.king(mover)], chess.Opponent(mover)) {
+                if err := g.push(idx, 0); err != nil {
+                    return err
+                }
+            } else {
+                g.done[idx] = true
+            }
+            continue
+        }
+
+        g.remaining[idx] = uint8(len(children))
+        switch {
+        case bestWin >= 0:
+            if err := g.push(idx, bestWin); err != nil {
+                return err
+            }
+        case len(children) == 0 && !g.escape[idx]:
+            if err := g.push(idx, int(g.exitWin[idx])); err != nil {
+                return err
+            }
+        case len(children) == 0:
+            g.done[idx] = true
+        }
+    }
+
+    return nil
+}
+
+// propagate settles the queued positions in order of distance and passes
+// each result on to the positions one move before it. Whatever is left
+// unsettled at the end is a draw.
+func (g *generator) propagate() error {
+    squares := make([]int, g.l.m.count())
+    preds := make([]int, 0, 64)
+
+    for dtm := range g.queue {
+        for i := 0; i < len(g.queue[dtm]); i++ {
+            idx := int(g.queue[dtm][i])
+            if g.done[idx] {
+                continue
+            }
+            g.done[idx] = true
+            g.data[idx] = byte(dtm + 1)
+
+            side := g.l.decode(idx, squares)
+            preds = g.predecessors(side, squares, preds[:0])
+            for _, p := range preds {
+                if g.done[p] {
+                    continue
+                }
+                if dtm%2 == 0 {
+                    if err := g.push(p, dtm+1); err != nil {
+                        return err
+                    }
+                    continue
+                }
+                g.remaining[p]--
+                if g.remaining[p] == 0 && !g.escape[p] {
+                    if err := g.push(p, max(dtm, int(g.exitWin[p])-1)+1); err != nil {
+                        return err
+                    }
+                }
+            }
+        }
+        g.queue[dtm] = nil
+    }
+
+    return nil
+}
+
+// predecessors appends the positions from which the side that isn't to
+// move could have reached this one with a quiet move: every piece stepping
+// back to an empty square it could have come from.
+func (g *generator) predecessors(side int, squares []int, preds []int) []int {
+    occupied := [boardSquares]bool{}
+    for _, sq := range squares {
+        occupied[sq] = true
+    }
+    var buf [MaxPieces]int
+    prev := buf[:len(squares)]
+    mover := chess.Opponent(sidePlayer(side))
+
+    add := func(i, from int) {
+        copy(prev, squares)
+        prev[i] = from
+        if idx := g.l.index(1-side, prev); idx >= 0 {
+            preds = addUnique(preds, idx)
+        }
+    }
+
+    for i, sq := range squares {
+        t, player := g.l.m.piece(i)
+        if player != mover {
+            continue
+        }
+        x, y := sq/chess.BoardSize, sq%chess.BoardSize
+
+        switch t {
+        case chess.PiecePawn:
+            dir := 1
+            if player == chess.PlayerBlack {
+                dir = -1
+            }
+            fx := x + dir
+            if fx <= 0 || fx >= chess.BoardSize-1 || occupied[fx*chess.BoardSize+y] {
+                continue
+            }
+            add(i, fx*chess.BoardSize+y)
+            if start := fx + dir; (dir == 1 && start == chess.BoardSize-2) || (dir == -1 && start == 1) {
+                if !occupied[start*chess.BoardSize+y] {
+                    add(i, start*chess.BoardSize+y)
+                }
+            }
+        case chess.PieceKnight, chess.PieceKing:
+            steps := kingSteps
+            if t == chess.PieceKnight {
+                steps = knightJumps
+            }
+            for _, d := range steps {
+                fx, fy := x+d[0], y+d[1]
+                if onBoard(fx, fy) && !occupied[fx*chess.BoardSize+fy] {
+                    add(i, fx*chess.BoardSize+fy)
+                }
+            }
+        default:
+            for _, d := range slides(t) {
+                for fx, fy := x+d[0], y+d[1]; onBoard(fx, fy) && !occupied[fx*chess.BoardSize+fy]; fx, fy = fx+d[0], fy+d[1] {
+                    add(i, fx*chess.BoardSize+fy)
+                }
+            }
+        }
+    }
+
+    return preds
+}
+
+func onBoard(x, y int) bool {
+    return x >= 0 && x < chess.BoardSize && y >= 0 && y < chess.BoardSize
+}
+
+func addUnique(list []int, v int) []int {
+    for _, e := range list {
+        if e == v {
+            return list
+        }
+    }
+    return append(list, v)
+}
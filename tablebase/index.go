@@ -0,0 +1,223 @@
+package tablebase
+
+import "goChess/chess"
+
+const boardSquares = chess.BoardSize * chess.BoardSize
+
+// broken marks entries that are never probed: illegal positions and
+// positions stored under another, symmetric index.
+const broken = 255
+
+// transforms are the eight symmetries of the board. Tables with pawns only
+// use the first two, since pawns can't be turned around or moved between
+// ranks.
+var transforms = [8]func(x, y int) (int, int){
+    func(x, y int) (int, int) { return x, y },
+    func(x, y int) (int, int) { return x, 7 - y },
+    func(x, y int) (int, int) { return 7 - x, y },
+    func(x, y int) (int, int) { return 7 - x, 7 - y },
+    func(x, y int) (int, int) { return y, x },
+    func(x, y int) (int, int) { return y, 7 - x },
+    func(x, y int) (int, int) { return 7 - y, x },
+    func(x, y int) (int, int) { return 7 - y, 7 - x },
+}
+
+// kingSlots maps the squares the white king may stand on in a canonical
+// position to a dense index, and back. Without pawns the king is kept in
+// the a1-d1-d4 triangle, with pawns on the a-d files.
+var (
+    pawnlessSlots, pawnSlots     []int
+    pawnlessSlotOf, pawnSlotOf   [boardSquares]int
+)
+
+// transformed holds where every transform takes every square, since index
+// is called for each move the generator looks at.
+var transformed [len(transforms)][boardSquares]int
+
+func init() {
+    for t, f := range transforms {
+        for sq := range transformed[t] {
+            x, y := f(sq/chess.BoardSize, sq%chess.BoardSize)
+            transformed[t][sq] = x*chess.BoardSize + y
+        }
+    }
+    for i := range pawnlessSlotOf {
+        pawnlessSlotOf[i], pawnSlotOf[i] = -1, -1
+    }
+    for x := 0; x < chess.BoardSize; x++ {
+        for y := 0; y < chess.BoardSize/2; y++ {
+            sq := x*chess.BoardSize + y
+            pawnSlotOf[sq] = len(pawnSlots)
+            pawnSlots = append(pawnSlots, sq)
+            if x >= chess.BoardSize-1-y {
+                pawnlessSlotOf[sq] = len(pawnlessSlots)
+                pawnlessSlots = append(pawnlessSlots, sq)
+            }
+        }
+    }
+}
+
+// layout describes how positions with a given material are numbered: the
+// side to move, the slot of the white king, then the square of every other
+// piece in material order.
+type layout struct {
+    m         material
+    slots     []int
+    slotOf    *[boardSquares]int
+    symmetric int
+    // identical lists the runs of identical pieces, as the first piece and
+    // the one after the last
+    identical [][2]int
+}
+
+func newLayout(m material) layout {
+    l := layout{m: m, slots: pawnlessSlots, slotOf: &pawnlessSlotOf, symmetric: 8}
+    if m.hasPawns() {
+        l.slots, l.slotOf, l.symmetric = pawnSlots, &pawnSlotOf, 2
+    }
+
+    start := 0
+    for i := 1; i <= m.count(); i++ {
+        if i < m.count() {
+            t, p := m.piece(i)
+            st, sp := m.piece(start)
+            if t == st && p == sp {
+                continue
+            }
+        }
+        if i-start > 1 {
+            l.identical = append(l.identical, [2]int{start, i})
+        }
+        start = i
+    }
+
+    return l
+}
+
+func (l layout) size() int {
+    n := 2 * len(l.slots)
+    for i := 1; i < l.m.count(); i++ {
+        n *= boardSquares
+    }
+    return n
+}
+
+// encode numbers the position as given, or returns -1 if the white king is
+// outside its slots.
+func (l layout) encode(side int, squares []int) int {
+    slot := l.slotOf[squares[0]]
+    if slot < 0 {
+        return -1
+    }
+    idx := side*len(l.slots) + slot
+    for _, sq := range squares[1:] {
+        idx = idx*boardSquares + sq
+    }
+    return idx
+}
+
+func (l layout) decode(idx int, squares []int) int {
+    for i := len(squares) - 1; i > 0; i-- {
+        squares[i] = idx % boardSquares
+        idx /= boardSquares
+    }
+    squares[0] = l.slots[idx%len(l.slots)]
+    return idx / len(l.slots)
+}
+
+// index returns the smallest number any symmetric image of the position
+// gets. Identical pieces are sorted by square first so that swapping them
+// doesn't change the result.
+func (l layout) index(side int, squares []int) int {
+    best := -1
+    var buf [MaxPieces]int
+    image := buf[:len(squares)]
+    for t := 0; t < l.symmetric; t++ {
+        for i, sq := range squares {
+            image[i] = transformed[t][sq]
+        }
+        l.sortIdentical(image)
+        if idx := l.encode(side, image); idx >= 0 && (best < 0 || idx < best) {
+            best = idx
+        }
+    }
+    return best
+}
+
+func (l layout) sortIdentical(squares []int) {
+    for _, run := range l.identical {
+        for i := run[0] + 1; i < run[1]; i++ {
+            for j := i; j > run[0] && squares[j] < squares[j-1]; j-- {
+                squares[j], squares[j-1] = squares[j-1], squares[j]
+            }
+        }
+    }
+}
+
+// sane reports whether the squares can hold the material at all: no two
+// pieces share a square and no pawn stands on the first or last rank.
+func (l layout) sane(squares []int) bool {
+    for i, sq := range squares {
+        for _, other := range squares[:i] {
+            if sq == other {
+                return false
+            }
+        }
+        if t, _ := l.m.piece(i); t == chess.PiecePawn {
+            if x := sq / chess.BoardSize; x == 0 || x == chess.BoardSize-1 {
+                return false
+            }
+        }
+    }
+    return true
+}
+
+func (l layout) board(squares []int) *chess.Board {
+    board := chess.NewChessBoard()
+    for i, sq := range squares {
+        t, p := l.m.piece(i)
+        board.SetPiece(sq/chess.BoardSize, sq%chess.BoardSize, chess.NewPiece(t, p))
+    }
+    board.SetCastlingRights("-")
+    return board
+}
+
+// squaresOf finds the pieces of the material on the board, with colors
+// swapped and the board turned around if flip is set.
+func (l layout) squaresOf(board *chess.Board, flip bool) []int {
+    squares := make([]int, l.m.count())
+    used := make([]bool, len(squares))
+    for x := 0; x < board.Height(); x++ {
+        for y := 0; y < board.Width(); y++ {
+            p := board.GetPiece(x, y)
+            if p.Type() == chess.PieceNone {
+                continue
+            }
+            player, sx := p.Player(), x
+            if flip {
+                player, sx = chess.Opponent(player), chess.BoardSize-1-x
+            }
+            for i := range squares {
+                if t, pl := l.m.piece(i); !used[i] && t == p.Type() && pl == player {
+                    squares[i], used[i] = sx*chess.BoardSize+y, true
+                    break
+                }
+            }
+        }
+    }
+    return squares
+}
+
+func sideIndex(player chess.PlayerType) int {
+    if player == chess.PlayerBlack {
+        return 1
+    }
+    return 0
+}
+
+func sidePlayer(side int) chess.PlayerType {
+    if side == 1 {
+        return chess.PlayerBlack
+    }
+    return chess.PlayerWhite
+}
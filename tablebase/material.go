@@ -0,0 +1,158 @@
+package tablebase
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+    "goChess/chess"
+)
+
+var InvalidMaterialError = fmt.Errorf("Invalid material")
+
+// MaxPieces is the largest number of pieces, kings included, a table can
+// be generated for.
+const MaxPieces = 4
+
+var pieceLetters = map[chess.PieceType]byte{
+    chess.PieceKing:   'K',
+    chess.PieceQueen:  'Q',
+    chess.PieceRook:   'R',
+    chess.PieceBishop: 'B',
+    chess.PieceKnight: 'N',
+    chess.PiecePawn:   'P',
+}
+
+var letterPieces = map[byte]chess.PieceType{
+    'Q': chess.PieceQueen,
+    'R': chess.PieceRook,
+    'B': chess.PieceBishop,
+    'N': chess.PieceKnight,
+    'P': chess.PiecePawn,
+}
+
+// material lists the pieces of both sides, each side starting with its
+// king and followed by the other pieces from the most valuable down.
+type material struct {
+    white []chess.PieceType
+    black []chess.PieceType
+}
+
+// parseMaterial reads a signature like "KQK" or "KBNK": the white pieces
+// starting with the white king, then the black pieces starting with the
+// black king.
+func parseMaterial(signature string) (material, error) {
+    s := strings.ToUpper(signature)
+    second := strings.IndexByte(s[min(1, len(s)):], 'K') + 1
+    if len(s) == 0 || s[0] != 'K' || second <= 0 {
+        return material{}, fmt.Errorf("%w: %q should name both kings", InvalidMaterialError, signature)
+    }
+
+    m := material{}
+    for side, part := range []string{s[1:second], s[second+1:]} {
+        pieces := []chess.PieceType{chess.PieceKing}
+        for i := 0; i < len(part); i++ {
+            t, ok := letterPieces[part[i]]
+            if !ok {
+                return material{}, fmt.Errorf("%w: unknown piece %q in %q", InvalidMaterialError, part[i], signature)
+            }
+            pieces = append(pieces, t)
+        }
+        sortPieces(pieces)
+        if side == 0 {
+            m.white = pieces
+        } else {
+            m.black = pieces
+        }
+    }
+
+    if m.count() > MaxPieces {
+        return material{}, fmt.Errorf("%w: %q has more than %d pieces", InvalidMaterialError, signature, MaxPieces)
+    }
+
+    return m, nil
+}
+
+func sortPieces(pieces []chess.PieceType) {
+    sort.SliceStable(pieces, func(i, j int) bool {
+        return chess.NewPiece(pieces[i], chess.PlayerWhite).Value() > chess.NewPiece(pieces[j], chess.PlayerWhite).Value()
+    })
+}
+
+func boardMaterial(board *chess.Board) material {
+    m := material{}
+    for i := 0; i < board.Height(); i++ {
+        for j := 0; j < board.Width(); j++ {
+            p := board.GetPiece(i, j)
+            switch p.Player() {
+            case chess.PlayerWhite:
+                m.white = append(m.white, p.Type())
+            case chess.PlayerBlack:
+                m.black = append(m.black, p.Type())
+            }
+        }
+    }
+    sortPieces(m.white)
+    sortPieces(m.black)
+
+    return m
+}
+
+func (m material) String() string {
+    var sb strings.Builder
+    for _, side := range [][]chess.PieceType{m.white, m.black} {
+        for _, t := range side {
+            sb.WriteByte(pieceLetters[t])
+        }
+    }
+    return sb.String()
+}
+
+func (m material) count() int {
+    return len(m.white) + len(m.black)
+}
+
+func (m material) valid() bool {
+    return len(m.white) > 0 && m.white[0] == chess.PieceKing && len(m.black) > 0 && m.black[0] == chess.PieceKing
+}
+
+func (m material) hasPawns() bool {
+    for _, side := range [][]chess.PieceType{m.white, m.black} {
+        for _, t := range side {
+            if t == chess.PiecePawn {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+func (m material) swapped() material {
+    return material{white: m.black, black: m.white}
+}
+
+func sideValue(pieces []chess.PieceType) int {
+    v := 0
+    for _, t := range pieces[1:] {
+        v += chess.NewPiece(t, chess.PlayerWhite).Value()
+    }
+    return v
+}
+
+// canonical reports whether tables store this material as is. The other
+// half is looked up with the colors swapped, so that only tables with the
+// stronger side as white exist.
+func (m material) canonical() bool {
+    w, b := sideValue(m.white), sideValue(m.black)
+    if w != b {
+        return w > b
+    }
+    return m.swapped().String() >= m.String()
+}
+
+// piece returns the type and color of the n-th piece of the material.
+func (m material) piece(n int) (chess.PieceType, chess.PlayerType) {
+    if n < len(m.white) {
+        return m.white[n], chess.PlayerWhite
+    }
+    return m.black[n-len(m.white)], chess.PlayerBlack
+}
@@ -0,0 +1,38 @@
+package tablebase
+
+import (
+    "testing"
+    "github.com/stretchr/testify/require"
+)
+
+func TestParseMaterial(t *testing.T) {
+    m, err := parseMaterial("KQK")
+    require.NoError(t, err)
+    require.Equal(t, "KQK", m.String())
+    require.True(t, m.canonical())
+
+    m, err = parseMaterial("knbk")
+    require.NoError(t, err)
+    require.Equal(t, "KBNK", m.String())
+
+    m, err = parseMaterial("KKR")
+    require.NoError(t, err)
+    require.False(t, m.canonical())
+    require.Equal(t, "KRK", m.swapped().String())
+
+    for _, signature := range []string{"", "QK", "KQ", "KXK", "KQRKR"} {
+        _, err := parseMaterial(signature)
+        require.ErrorIs(t, err, InvalidMaterialError, signature)
+    }
+}
+
+func TestSuccessors(t *testing.T) {
+    m, err := parseMaterial("KPKR")
+    require.NoError(t, err)
+
+    names := []string{}
+    for _, sub := range m.successors() {
+        names = append(names, sub.String())
+    }
+    require.ElementsMatch(t, []string{"KKR", "KQKR", "KQK", "KRKR", "KRK", "KBKR", "KBK", "KNKR", "KNK", "KPK"}, names)
+}
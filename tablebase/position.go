@@ -0,0 +1,218 @@
+package tablebase
+
+import "goChess/chess"
+
+// position is a position of the material of a layout, held as the square
+// of every piece and, the other way round, the piece on every square. It
+// generates moves much faster than a chess.Board, which the generator only
+// builds for moves that leave the table.
+type position struct {
+    l       *layout
+    squares []int
+    // at is the piece on every square, or -1
+    at      [boardSquares]int8
+}
+
+func newPosition(l *layout) *position {
+    return &position{l: l, squares: make([]int, l.m.count())}
+}
+
+// set puts the pieces on squares.
+func (p *position) set(squares []int) {
+    copy(p.squares, squares)
+    for i := range p.at {
+        p.at[i] = -1
+    }
+    for i, sq := range p.squares {
+        p.at[sq] = int8(i)
+    }
+}
+
+// king returns the piece number of the king of player.
+func (p *position) king(player chess.PlayerType) int {
+    if player == chess.PlayerBlack {
+        return len(p.l.m.white)
+    }
+    return 0
+}
+
+// attacked reports whether a piece of player attacks square target.
+func (p *position) attacked(target int, player chess.PlayerType) bool {
+    tx, ty := target/chess.BoardSize, target%chess.BoardSize
+    for i, sq := range p.squares {
+        t, pl := p.l.m.piece(i)
+        if sq < 0 || pl != player {
+            continue
+        }
+        x, y := sq/chess.BoardSize, sq%chess.BoardSize
+        dx, dy := tx-x, ty-y
+
+        switch t {
+        case chess.PiecePawn:
+            dir := -1
+            if player == chess.PlayerBlack {
+                dir = 1
+            }
+            if dx == dir && (dy == 1 || dy == -1) {
+                return true
+            }
+        case chess.PieceKnight:
+            if dx*dx+dy*dy == 5 {
+                return true
+            }
+        case chess.PieceKing:
+            if dx*dx <= 1 && dy*dy <= 1 && (dx != 0 || dy != 0) {
+                return true
+            }
+        default:
+            straight := (dx == 0) != (dy == 0)
+            diagonal := dx != 0 && (dx == dy || dx == -dy)
+            if (straight && t != chess.PieceBishop || diagonal && t != chess.PieceRook) && p.clear(x, y, tx, ty) {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// clear reports whether the squares between (x, y) and (tx, ty), on one
+// line, are empty.
+func (p *position) clear(x, y, tx, ty int) bool {
+    sx, sy := sign(tx-x), sign(ty-y)
+    for x, y = x+sx, y+sy; x != tx || y != ty; x, y = x+sx, y+sy {
+        if p.at[x*chess.BoardSize+y] >= 0 {
+            return false
+        }
+    }
+    return true
+}
+
+func sign(n int) int {
+    switch {
+    case n > 0:
+        return 1
+    case n < 0:
+        return -1
+    }
+    return 0
+}
+
+// targets appends the squares piece i moves to, empty or holding a piece of
+// the other side, whether or not the move leaves its king in check.
+func (p *position) targets(i int, targets []int) []int {
+    t, player := p.l.m.piece(i)
+    sq := p.squares[i]
+    x, y := sq/chess.BoardSize, sq%chess.BoardSize
+
+    // enters reports whether the piece may stand on (tx, ty), and whether
+    // it has to stop there
+    enters := func(tx, ty int) (bool, bool) {
+        if !onBoard(tx, ty) {
+            return false, true
+        }
+        other := p.at[tx*chess.BoardSize+ty]
+        if other < 0 {
+            return true, false
+        }
+        _, pl := p.l.m.piece(int(other))
+        return pl != player, true
+    }
+
+    switch t {
+    case chess.PiecePawn:
+        dir, start := -1, chess.BoardSize-2
+        if player == chess.PlayerBlack {
+            dir, start = 1, 1
+        }
+        if fx := x + dir; onBoard(fx, y) && p.at[fx*chess.BoardSize+y] < 0 {
+            targets = append(targets, fx*chess.BoardSize+y)
+            if lx := fx + dir; x == start && p.at[lx*chess.BoardSize+y] < 0 {
+                targets = append(targets, lx*chess.BoardSize+y)
+            }
+        }
+        for _, dy := range []int{-1, 1} {
+            if ok, stop := enters(x+dir, y+dy); ok && stop {
+                targets = append(targets, (x+dir)*chess.BoardSize+y+dy)
+            }
+        }
+    case chess.PieceKnight, chess.PieceKing:
+        steps := kingSteps
+        if t == chess.PieceKnight {
+            steps = knightJumps
+        }
+        for _, d := range steps {
+            if ok, _ := enters(x+d[0], y+d[1]); ok {
+                targets = append(targets, (x+d[0])*chess.BoardSize+y+d[1])
+            }
+        }
+    default:
+        for _, d := range slides(t) {
+            for tx, ty := x+d[0], y+d[1]; ; tx, ty = tx+d[0], ty+d[1] {
+                ok, stop := enters(tx, ty)
+                if ok {
+                    targets = append(targets, tx*chess.BoardSize+ty)
+                }
+                if stop {
+                    break
+                }
+            }
+        }
+    }
+
+    return targets
+}
+
+var queenDirs = append(append([][2]int{}, rookDirs...), bishopDirs...)
+
+// slides returns the directions a rook, bishop or queen moves in.
+func slides(t chess.PieceType) [][2]int {
+    switch t {
+    case chess.PieceRook:
+        return rookDirs
+    case chess.PieceBishop:
+        return bishopDirs
+    }
+    return queenDirs
+}
+
+// move puts piece i on square to and takes the piece there, if any. It
+// returns the piece taken, or -1, for undo.
+func (p *position) move(i, to int) int {
+    taken := int(p.at[to])
+    if taken >= 0 {
+        p.squares[taken] = -1
+    }
+    p.at[p.squares[i]] = -1
+    p.at[to] = int8(i)
+    p.squares[i] = to
+    return taken
+}
+
+// undo takes back the move of piece i from square from that took taken.
+func (p *position) undo(i, from, taken int) {
+    to := p.squares[i]
+    p.at[to] = int8(taken)
+    if taken >= 0 {
+        p.squares[taken] = to
+    }
+    p.squares[i] = from
+    p.at[from] = int8(i)
+}
+
+// board returns the position as a chess.Board, without the pieces taken and
+// with a pawn on the last rank promoted to a piece of type promoted.
+func (p *position) board(promoted chess.PieceType) *chess.Board {
+    board := chess.NewChessBoard()
+    for i, sq := range p.squares {
+        if sq < 0 {
+            continue
+        }
+        t, pl := p.l.m.piece(i)
+        if x := sq / chess.BoardSize; t == chess.PiecePawn && (x == 0 || x == chess.BoardSize-1) {
+            t = promoted
+        }
+        board.SetPiece(sq/chess.BoardSize, sq%chess.BoardSize, chess.NewPiece(t, pl))
+    }
+    board.SetCastlingRights("-")
+    return board
+}
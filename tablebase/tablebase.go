@@ -0,0 +1,150 @@
+// Package tablebase generates endgame tables of up to four pieces by
+// retrograde analysis, and stores them in its own file format. It does not
+// read Syzygy tables.
+package tablebase
+
+import (
+    "goChess/chess"
+)
+
+// Result is the outcome of a position for the side to move, with perfect
+// play from both sides. It is the chess package's type so that a Set can
+// be registered with chess.SetTablebase.
+type Result = chess.TablebaseResult
+
+const (
+    Win  = chess.TablebaseWin
+    Draw = chess.TablebaseDraw
+    Loss = chess.TablebaseLoss
+)
+
+// Table holds the result and distance to mate of every position with one
+// material, one byte per position. 0 is a draw; any other value v below
+// 255 means mate in v-1 plies, delivered by the side to move if that is
+// odd and against it if even.
+type Table struct {
+    m    material
+    data []byte
+}
+
+// Material returns the signature of the table, like "KQK".
+func (t *Table) Material() string {
+    return t.m.String()
+}
+
+func decodeValue(v byte) (Result, int) {
+    switch {
+    case v == 0:
+        return Draw, 0
+    case (v-1)%2 == 1:
+        return Win, int(v - 1)
+    default:
+        return Loss, int(v - 1)
+    }
+}
+
+// Set is a collection of tables, looked up by material.
+type Set struct {
+    tables map[string]*Table
+}
+
+func NewSet() *Set {
+    return &Set{tables: make(map[string]*Table)}
+}
+
+func (s *Set) Add(t *Table) {
+    s.tables[t.m.String()] = t
+}
+
+// Table returns the table for signature, with the colors swapped if the
+// weaker side is given first.
+func (s *Set) Table(signature string) (*Table, bool) {
+    m, err := parseMaterial(signature)
+    if err != nil {
+        return nil, false
+    }
+    if !m.canonical() {
+        m = m.swapped()
+    }
+    t, ok := s.tables[m.String()]
+    return t, ok
+}
+
+// Probe returns the result for toMove and the number of plies to mate. ok
+// is false when the set has no table for the material on the board or the
+// position is illegal. Castling rights are ignored.
+func (s *Set) Probe(board *chess.Board, toMove chess.PlayerType) (Result, int, bool) {
+    v, ok := s.value(board, toMove)
+    if !ok {
+        return Draw, 0, false
+    }
+    res, dtm := decodeValue(v)
+    return res, dtm, true
+}
+
+func (s *Set) value(board *chess.Board, toMove chess.PlayerType) (byte, bool) {
+    if board.Width() != chess.BoardSize || board.Height() != chess.BoardSize {
+        return 0, false
+    }
+    m := boardMaterial(board)
+    if !m.valid() || m.count() > MaxPieces {
+        return 0, false
+    }
+    if m.count() == 2 {
+        return 0, true
+    }
+
+    flip := !m.canonical()
+    if flip {
+        m, toMove = m.swapped(), chess.Opponent(toMove)
+    }
+    t, ok := s.tables[m.String()]
+    if !ok {
+        return 0, false
+    }
+
+    l := newLayout(m)
+    idx := l.index(sideIndex(toMove), l.squaresOf(board, flip))
+    if idx < 0 || t.data[idx] == broken {
+        return 0, false
+    }
+
+    return t.data[idx], true
+}
+
+// BestMove returns a move that keeps the result of the position: the
+// fastest mate when winning, a drawing move when drawn, and the longest
+// defence when lost.
+func (s *Set) BestMove(board *chess.Board, toMove chess.PlayerType) (chess.Move, bool) {
+    res, _, ok := s.Probe(board, toMove)
+    if !ok {
+        return chess.Move{}, false
+    }
+
+    best, bestDtm, found := chess.Move{}, 0, false
+    for _, m := range board.LegalMoves(toMove) {
+        next, err := board.MakeMove(m)
+        if err != nil {
+            continue
+        }
+        reply, dtm, ok := s.Probe(next, chess.Opponent(toMove))
+        if !ok {
+            continue
+        }
+
+        better := false
+        switch res {
+        case Win:
+            better = reply == Loss && (!found || dtm < bestDtm)
+        case Draw:
+            better = reply == Draw && !found
+        case Loss:
+            better = reply == Win && (!found || dtm > bestDtm)
+        }
+        if better {
+            best, bestDtm, found = m, dtm, true
+        }
+    }
+
+    return best, found
+}
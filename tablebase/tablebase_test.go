@@ -0,0 +1,251 @@
+package tablebase
+
+import (
+    "bytes"
+    "math/rand"
+    "sync"
+    "testing"
+    "goChess/chess"
+    "github.com/stretchr/testify/require"
+)
+
+var (
+    testSet     *Set
+    testSetOnce sync.Once
+)
+
+// generated returns a set with the KQK, KRK and KPK tables, built once for
+// all tests since generation takes a few seconds.
+func generated(t *testing.T) *Set {
+    testSetOnce.Do(func() {
+        testSet = NewSet()
+        for _, signature := range []string{"KRK", "KPK"} {
+            if _, err := testSet.Generate(signature); err != nil {
+                panic(err)
+            }
+        }
+    })
+    return testSet
+}
+
+func probe(t *testing.T, s *Set, fen string) (Result, int) {
+    board, toMove, err := chess.ParseFEN(fen)
+    require.NoError(t, err)
+    res, dtm, ok := s.Probe(board, toMove)
+    require.True(t, ok, fen)
+    return res, dtm
+}
+
+func TestGenerateSubTables(t *testing.T) {
+    s := generated(t)
+    for _, signature := range []string{"KQK", "KRK", "KPK", "KKQ"} {
+        _, ok := s.Table(signature)
+        require.True(t, ok, signature)
+    }
+    _, ok := s.Table("KBNK")
+    require.False(t, ok)
+}
+
+func TestProbe(t *testing.T) {
+    s := generated(t)
+
+    res, dtm := probe(t, s, "k7/8/1K6/8/8/8/8/6Q1 w - - 0 1")
+    require.Equal(t, Win, res)
+    require.Equal(t, 1, dtm)
+
+    res, dtm = probe(t, s, "k5Q1/8/1K6/8/8/8/8/8 b - - 0 1")
+    require.Equal(t, Loss, res)
+    require.Equal(t, 0, dtm)
+
+    // the same positions with the colors swapped
+    res, dtm = probe(t, s, "6q1/8/8/8/8/1k6/8/K7 b - - 0 1")
+    require.Equal(t, Win, res)
+    require.Equal(t, 1, dtm)
+
+    res, _ = probe(t, s, "4k3/8/4K3/4P3/8/8/8/8 w - - 0 1")
+    require.Equal(t, Win, res)
+    res, _ = probe(t, s, "8/8/8/8/8/4k3/4P3/4K3 w - - 0 1")
+    require.Equal(t, Draw, res)
+    // stalemate
+    res, _ = probe(t, s, "4k3/4P3/4K3/8/8/8/8/8 b - - 0 1")
+    require.Equal(t, Draw, res)
+    // only kings
+    res, _ = probe(t, s, "4k3/8/4K3/8/8/8/8/8 b - - 0 1")
+    require.Equal(t, Draw, res)
+
+    board, _, err := chess.ParseFEN("8/8/8/8/8/8/8/k2Q1K2 b - - 0 1")
+    require.NoError(t, err)
+    _, _, ok := s.Probe(board, chess.PlayerWhite)
+    require.False(t, ok, "the side not to move is in check")
+}
+
+// deepestWin returns the distance of the longest win in the table, in
+// plies.
+func deepestWin(table *Table) int {
+    deepest := 0
+    for _, v := range table.data {
+        if v != broken {
+            if res, dtm := decodeValue(v); res == Win && dtm > deepest {
+                deepest = dtm
+            }
+        }
+    }
+    return deepest
+}
+
+func TestLongestMates(t *testing.T) {
+    s := generated(t)
+    // the longest wins are 10 moves for KQK and 16 moves for KRK
+    for signature, longest := range map[string]int{"KQK": 19, "KRK": 31} {
+        table, ok := s.Table(signature)
+        require.True(t, ok)
+        require.Equal(t, longest, deepestWin(table), signature)
+    }
+}
+
+func TestKBNKLongestMate(t *testing.T) {
+    if testing.Short() {
+        t.Skip("generating KBNK takes a few seconds")
+    }
+    // the bishop and knight mate takes 33 moves at most
+    table, err := NewSet().Generate("KBNK")
+    require.NoError(t, err)
+    require.Equal(t, 65, deepestWin(table))
+}
+
+// TestConsistency checks the distances against the moves of random
+// positions: a win needs a move to a loss one ply shorter, and a loss has
+// only moves to wins, the longest one ply shorter.
+func TestConsistency(t *testing.T) {
+    s := generated(t)
+    r := rand.New(rand.NewSource(7))
+    players := []chess.PlayerType{chess.PlayerWhite, chess.PlayerBlack}
+
+    for _, pieces := range [][]chess.PieceType{{chess.PieceRook}, {chess.PieceQueen}, {chess.PiecePawn}} {
+        for checked := 0; checked < 150; {
+            board := chess.NewChessBoard()
+            board.SetPiece(r.Intn(8), r.Intn(8), chess.NewPiece(chess.PieceKing, chess.PlayerWhite))
+            board.SetPiece(r.Intn(8), r.Intn(8), chess.NewPiece(chess.PieceKing, chess.PlayerBlack))
+            board.SetPiece(1+r.Intn(6), r.Intn(8), chess.NewPiece(pieces[0], players[r.Intn(2)]))
+            board.SetCastlingRights("-")
+            toMove := players[r.Intn(2)]
+
+            res, dtm, ok := s.Probe(board, toMove)
+            if !ok || len(boardMaterial(board).white)+len(boardMaterial(board).black) != 3 {
+                continue
+            }
+            checked++
+
+            replies := map[Result][]int{}
+            for _, m := range board.LegalMoves(toMove) {
+                next, err := board.MakeMove(m)
+                require.NoError(t, err)
+                reply, d, ok := s.Probe(next, chess.Opponent(toMove))
+                require.True(t, ok)
+                replies[reply] = append(replies[reply], d)
+            }
+
+            fen := board.FEN(toMove)
+            switch res {
+            case Win:
+                require.Contains(t, replies[Loss], dtm-1, fen)
+                for _, d := range replies[Loss] {
+                    require.GreaterOrEqual(t, d, dtm-1, fen)
+                }
+            case Loss:
+                require.Empty(t, replies[Draw], fen)
+                require.Empty(t, replies[Loss], fen)
+                if dtm > 0 {
+                    require.Contains(t, replies[Win], dtm-1, fen)
+                }
+                for _, d := range replies[Win] {
+                    require.LessOrEqual(t, d, dtm-1, fen)
+                }
+            case Draw:
+                require.Empty(t, replies[Loss], fen)
+            }
+        }
+    }
+}
+
+func TestBestMove(t *testing.T) {
+    s := generated(t)
+
+    board, toMove, err := chess.ParseFEN("k7/8/1K6/8/8/8/8/6Q1 w - - 0 1")
+    require.NoError(t, err)
+    m, ok := s.BestMove(board, toMove)
+    require.True(t, ok)
+    require.Equal(t, "g1g8", m.String())
+
+    // play the KRK mate out from a long win
+    board, toMove, err = chess.ParseFEN("8/8/8/3k4/8/8/8/R3K3 w - - 0 1")
+    require.NoError(t, err)
+    _, dtm, ok := s.Probe(board, toMove)
+    require.True(t, ok)
+    for ply := 0; ply < dtm; ply++ {
+        m, ok := s.BestMove(board, toMove)
+        require.True(t, ok)
+        board, err = board.MakeMove(m)
+        require.NoError(t, err)
+        toMove = chess.Opponent(toMove)
+    }
+    require.True(t, board.IsCheckmate(toMove))
+}
+
+func TestReadWrite(t *testing.T) {
+    s := generated(t)
+    table, ok := s.Table("KPK")
+    require.True(t, ok)
+
+    var buf bytes.Buffer
+    require.NoError(t, table.Write(&buf))
+    require.Less(t, buf.Len(), len(table.data)/4)
+
+    read, err := Read(&buf)
+    require.NoError(t, err)
+    require.Equal(t, "KPK", read.Material())
+    require.Equal(t, table.data, read.data)
+
+    _, err = Read(bytes.NewReader([]byte("GCTB\x09")))
+    require.ErrorIs(t, err, InvalidTableError)
+    _, err = Read(bytes.NewReader([]byte("nope, not a table")))
+    require.ErrorIs(t, err, InvalidTableError)
+
+    // the start of a Syzygy WDL file
+    _, err = Read(bytes.NewReader([]byte("\x71\xe8\x23\x5d\x00\x00\x00\x00")))
+    require.ErrorIs(t, err, SyzygyTableError)
+}
+
+func TestBoardProbeTablebase(t *testing.T) {
+    board, toMove, err := chess.ParseFEN("k7/8/1K6/8/8/8/8/6Q1 w - - 0 1")
+    require.NoError(t, err)
+    _, ok := board.ProbeTablebase(toMove)
+    require.False(t, ok, "no tables are registered")
+
+    chess.SetTablebase(generated(t))
+    defer chess.SetTablebase(nil)
+
+    entry, ok := board.ProbeTablebase(toMove)
+    require.True(t, ok)
+    require.Equal(t, chess.TablebaseEntry{Result: Win, DTM: 1, Move: chess.NewMove(7, 6, 0, 6)}, entry)
+
+    // mated, with no move to play
+    board, toMove, err = chess.ParseFEN("k5Q1/8/1K6/8/8/8/8/8 b - - 0 1")
+    require.NoError(t, err)
+    entry, ok = board.ProbeTablebase(toMove)
+    require.True(t, ok)
+    require.Equal(t, chess.TablebaseEntry{Result: Loss}, entry)
+
+    board, toMove, err = chess.ParseFEN("8/8/8/8/8/4k3/4P3/4K3 w - - 0 1")
+    require.NoError(t, err)
+    entry, ok = board.ProbeTablebase(toMove)
+    require.True(t, ok)
+    require.Equal(t, Draw, entry.Result)
+    require.Contains(t, board.LegalMoves(toMove), entry.Move)
+
+    // no table for the material
+    board, toMove, err = chess.ParseFEN("k7/8/1K6/8/8/8/8/5BN1 w - - 0 1")
+    require.NoError(t, err)
+    _, ok = board.ProbeTablebase(toMove)
+    require.False(t, ok)
+}
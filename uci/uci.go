@@ -0,0 +1,514 @@
+// Package uci runs the engine behind the Universal Chess Interface, the
+// text protocol chess GUIs and tournament managers talk to engines in.
+package uci
+
+import (
+    "bufio"
+    "context"
+    "fmt"
+    "io"
+    "math/rand"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+    "goChess/book"
+    "goChess/chess"
+    "goChess/engine"
+)
+
+var UnknownCommandError = fmt.Errorf("Unknown command")
+var UnknownOptionError = fmt.Errorf("Unknown option")
+var InvalidOptionError = fmt.Errorf("Invalid option value")
+var InvalidPositionError = fmt.Errorf("Invalid position")
+var InvalidGoError = fmt.Errorf("Invalid go command")
+var NoPositionError = fmt.Errorf("No position to search")
+
+const name = "goChess"
+
+// maxMultiPV is the most lines a search can be asked for, more than any
+// position has legal moves.
+const maxMultiPV = 256
+
+// UCI reads commands from a GUI and writes the engine's answers. Searches
+// run in the background, so that "stop" and "isready" are answered while
+// the engine thinks.
+type UCI struct {
+    out    io.Writer
+    outMu  sync.Mutex
+    engine *engine.Engine
+    game   *chess.Game
+    // positionErr is why the last position could not be set up
+    positionErr error
+    search *search
+    // ponder is set when the GUI lets the engine think on the opponent's
+    // time, and the best move is sent with the move to ponder on
+    ponder bool
+    // chess960 writes castling as the king taking its own rook
+    chess960 bool
+    // ownBook plays the moves of book, picked at random by weight, while
+    // it has some for the position
+    ownBook bool
+    book    *book.Book
+    rand    *rand.Rand
+}
+
+// search is a search running in the background. done is closed once its
+// best move has been sent; release lets a search that has to wait for the
+// GUI send it.
+type search struct {
+    cancel  context.CancelFunc
+    time    *engine.TimeManager
+    done    chan struct{}
+    release chan struct{}
+    once    sync.Once
+}
+
+func (s *search) releaseMove() {
+    s.once.Do(func() { close(s.release) })
+}
+
+func New(out io.Writer) *UCI {
+    return &UCI{out: out, engine: engine.NewEngine(engine.DefaultHashMB), rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Run answers the commands read from in until "quit" or the end of the
+// input, which stops the search.
+func (u *UCI) Run(in io.Reader) error {
+    scanner := bufio.NewScanner(in)
+    for scanner.Scan() {
+        if !u.execute(scanner.Text()) {
+            return nil
+        }
+    }
+    u.stop()
+    return scanner.Err()
+}
+
+// execute runs a single command, and reports false on "quit". Errors are
+// sent to the GUI as info strings, since the protocol has no other way to
+// report them.
+func (u *UCI) execute(line string) bool {
+    fields := strings.Fields(line)
+    if len(fields) == 0 {
+        return true
+    }
+
+    var err error
+    switch fields[0] {
+    case "uci":
+        u.identify()
+    case "isready":
+        u.send("readyok")
+    case "setoption":
+        u.stop()
+        err = u.setOption(fields[1:])
+    case "ucinewgame":
+        u.stop()
+        u.engine.NewGame()
+        u.game, u.positionErr = nil, nil
+    case "position":
+        u.stop()
+        err = u.setPosition(fields[1:])
+    case "go":
+        u.stop()
+        err = u.goSearch(fields[1:])
+    case "stop":
+        u.stop()
+    case "ponderhit":
+        u.ponderHit()
+    case "quit":
+        u.stop()
+        return false
+    default:
+        err = fmt.Errorf("%w: %q", UnknownCommandError, fields[0])
+    }
+
+    if err != nil {
+        u.send("info string " + err.Error())
+    }
+    return true
+}
+
+func (u *UCI) send(format string, args ...any) {
+    u.outMu.Lock()
+    defer u.outMu.Unlock()
+    fmt.Fprintf(u.out, format+"\n", args...)
+}
+
+func (u *UCI) identify() {
+    u.send("id name %v", name)
+    u.send("id author the %v authors", name)
+    for _, o := range options {
+        u.send("%v", o)
+    }
+    u.send("uciok")
+}
+
+// emptyString is how the protocol writes the default of a string option
+// that has none.
+const emptyString = "<empty>"
+
+// option is an option the GUI can set.
+type option struct {
+    name     string
+    kind     string
+    def      string
+    min, max int
+    set      func(u *UCI, o option, value string) error
+}
+
+func (o option) String() string {
+    s := fmt.Sprintf("option name %v type %v default %v", o.name, o.kind, o.def)
+    if o.kind == "spin" {
+        s += fmt.Sprintf(" min %d max %d", o.min, o.max)
+    }
+    return s
+}
+
+// spin reads the value of a spin option.
+func (o option) spin(value string) (int, error) {
+    n, err := strconv.Atoi(value)
+    if err != nil || n < o.min || n > o.max {
+        return 0, fmt.Errorf("%w: %v should be a number from %d to %d, got %q", InvalidOptionError, o.name, o.min, o.max, value)
+    }
+    return n, nil
+}
+
+// check reads the value of a check option.
+func (o option) check(value string) (bool, error) {
+    switch value {
+    case "true":
+        return true, nil
+    case "false":
+        return false, nil
+    }
+    return false, fmt.Errorf("%w: %v should be true or false, got %q", InvalidOptionError, o.name, value)
+}
+
+var options = []option{
+    {name: "Hash", kind: "spin", def: strconv.Itoa(engine.DefaultHashMB), min: engine.MinHashMB, max: engine.MaxHashMB,
+        set: func(u *UCI, o option, value string) error {
+            n, err := o.spin(value)
+            if err == nil {
+                u.engine.SetHash(n)
+            }
+            return err
+        }},
+    {name: "Threads", kind: "spin", def: "1", min: 1, max: engine.MaxThreads,
+        set: func(u *UCI, o option, value string) error {
+            n, err := o.spin(value)
+            if err == nil {
+                u.engine.SetThreads(n)
+            }
+            return err
+        }},
+    {name: "MultiPV", kind: "spin", def: "1", min: 1, max: maxMultiPV,
+        set: func(u *UCI, o option, value string) error {
+            n, err := o.spin(value)
+            if err == nil {
+                u.engine.SetMultiPV(n)
+            }
+            return err
+        }},
+    {name: "Ponder", kind: "check", def: "false",
+        set: func(u *UCI, o option, value string) error {
+            b, err := o.check(value)
+            if err == nil {
+                u.ponder = b
+            }
+            return err
+        }},
+    {name: "OwnBook", kind: "check", def: "false",
+        set: func(u *UCI, o option, value string) error {
+            b, err := o.check(value)
+            if err == nil {
+                u.ownBook = b
+            }
+            return err
+        }},
+    {name: "BookFile", kind: "string", def: emptyString,
+        set: func(u *UCI, o option, value string) error {
+            if value == "" || value == emptyString {
+                u.book = nil
+                return nil
+            }
+            b, err := book.Open(value)
+            if err != nil {
+                return fmt.Errorf("%w: %v: %w", InvalidOptionError, o.name, err)
+            }
+            u.book = b
+            return nil
+        }},
+    {name: "UCI_Chess960", kind: "check", def: "false",
+        set: func(u *UCI, o option, value string) error {
+            b, err := o.check(value)
+            if err == nil {
+                u.chess960 = b
+            }
+            return err
+        }},
+}
+
+// setOption handles "setoption name <name> value <value>". Option names
+// are matched without regard to case, as the protocol asks.
+func (u *UCI) setOption(args []string) error {
+    if len(args) == 0 || args[0] != "name" {
+        return fmt.Errorf("%w: expected a name", UnknownOptionError)
+    }
+    value := len(args)
+    for i, a := range args {
+        if a == "value" {
+            value = i
+            break
+        }
+    }
+    name := strings.Join(args[1:value], " ")
+
+    for _, o := range options {
+        if strings.EqualFold(o.name, name) {
+            return o.set(u, o, strings.Join(args[min(value+1, len(args)):], " "))
+        }
+    }
+    return fmt.Errorf("%w: %q", UnknownOptionError, name)
+}
+
+// setPosition handles "position startpos|fen <fen> [moves <moves>]". A
+// position that fails leaves no game to search, and its error is kept for
+// "go" to report.
+func (u *UCI) setPosition(args []string) error {
+    u.game, u.positionErr = u.parsePosition(args)
+    return u.positionErr
+}
+
+func (u *UCI) parsePosition(args []string) (*chess.Game, error) {
+    if len(args) == 0 {
+        return nil, fmt.Errorf("%w: expected startpos or fen", InvalidPositionError)
+    }
+
+    moves := len(args)
+    for i, a := range args {
+        if a == "moves" {
+            moves = i
+            break
+        }
+    }
+
+    var g *chess.Game
+    var err error
+    switch args[0] {
+    case "startpos":
+        g, err = chess.NewGame(chess.Standard{})
+    case "fen":
+        fen := strings.Join(args[1:moves], " ")
+        if u.chess960 {
+            g, err = chess.NewChess960GameFromFEN(chess.Standard{}, fen)
+        } else {
+            g, err = chess.NewGameFromFEN(chess.Standard{}, fen)
+        }
+    default:
+        err = fmt.Errorf("expected startpos or fen, got %q", args[0])
+    }
+    if err != nil {
+        return nil, fmt.Errorf("%w: %w", InvalidPositionError, err)
+    }
+    // the starting position is Chess960 too, with the king taking its rook
+    // to castle
+    if u.chess960 {
+        g.Board().SetChess960(true)
+    }
+
+    for _, s := range args[min(moves+1, len(args)):] {
+        m, ok := findMove(g, s)
+        if !ok {
+            return nil, fmt.Errorf("%w: %w: %v", InvalidPositionError, chess.IllegalMoveError, s)
+        }
+        if err := g.Play(m); err != nil {
+            return nil, fmt.Errorf("%w: %w", InvalidPositionError, err)
+        }
+    }
+
+    return g, nil
+}
+
+// findMove finds the legal move written s in UCI notation.
+func findMove(g *chess.Game, s string) (chess.Move, bool) {
+    for _, m := range g.LegalMoves() {
+        if g.Board().UCI(m) == s {
+            return m, true
+        }
+    }
+    return chess.Move{}, false
+}
+
+// goSearch handles "go", starting a search of the current position in the
+// background. A search on the clock gets a time manager; so does a search
+// while pondering, which runs until "ponderhit" starts the clock. With
+// OwnBook on, a book move is played without searching.
+func (u *UCI) goSearch(args []string) error {
+    limits := engine.Limits{}
+    tc := engine.TimeControl{}
+    timed, ponder := false, false
+    for i := 0; i < len(args); i++ {
+        switch args[i] {
+        case "infinite":
+            tc.Infinite = true
+            continue
+        case "ponder":
+            ponder = true
+            continue
+        }
+
+        if i+1 >= len(args) {
+            return fmt.Errorf("%w: %v needs a value", InvalidGoError, args[i])
+        }
+        n, err := strconv.ParseInt(args[i+1], 10, 64)
+        if err != nil {
+            return fmt.Errorf("%w: %v: %w", InvalidGoError, args[i], err)
+        }
+        // clocks may run below zero in some GUIs
+        ms := time.Duration(n) * time.Millisecond
+        switch args[i] {
+        case "depth":
+            limits.Depth = int(n)
+        case "nodes":
+            limits.Nodes = uint64(max(n, 0))
+        case "wtime":
+            tc.WTime, timed = ms, true
+        case "btime":
+            tc.BTime, timed = ms, true
+        case "winc":
+            tc.WInc = ms
+        case "binc":
+            tc.BInc = ms
+        case "movestogo":
+            tc.MovesToGo = int(n)
+        case "movetime":
+            tc.MoveTime, timed = ms, true
+        default:
+            return fmt.Errorf("%w: unknown parameter %q", InvalidGoError, args[i])
+        }
+        i++
+    }
+
+    if u.positionErr != nil {
+        // report it before the move the GUI still waits for
+        u.send("info string %v", fmt.Errorf("%w: %w", NoPositionError, u.positionErr))
+        u.send("bestmove 0000")
+        return nil
+    }
+    if u.game == nil {
+        if err := u.setPosition([]string{"startpos"}); err != nil {
+            return err
+        }
+    }
+    board, toMove := u.game.Board(), u.game.ToMove()
+    bookMove, inBook := u.bookMove()
+    if timed || ponder {
+        limits.Time = engine.NewTimeManager(tc, toMove, ponder)
+    }
+
+    ctx, cancel := context.WithCancel(context.Background())
+    s := &search{cancel: cancel, time: limits.Time, done: make(chan struct{}), release: make(chan struct{})}
+    u.search = s
+    start := time.Now()
+    // the lines are sent after every iteration, and once more at the end
+    // if the search completed none, as when played from the tables
+    reported := 0
+    limits.OnIteration = func(r engine.Result) {
+        u.report(board, r, time.Since(start))
+        reported = r.Depth
+    }
+    go func() {
+        defer close(s.done)
+        r := engine.Result{Move: bookMove, PV: []chess.Move{bookMove}}
+        if !inBook {
+            r = u.engine.Search(ctx, board, toMove, limits)
+            if r.Depth != reported {
+                u.report(board, r, time.Since(start))
+            }
+        }
+        // the move of an infinite search waits for "stop", that of a
+        // pondering one for "stop" or "ponderhit"
+        if tc.Infinite || ponder {
+            <-s.release
+        }
+        u.bestMove(board, r)
+    }()
+
+    return nil
+}
+
+// bookMove picks a move of the book for the current position, when
+// OwnBook is on.
+func (u *UCI) bookMove() (chess.Move, bool) {
+    if !u.ownBook || u.book == nil {
+        return chess.Move{}, false
+    }
+    last := chess.Move{}
+    if moves := u.game.Moves(); len(moves) > 0 {
+        last = moves[len(moves)-1]
+    }
+    return u.book.Pick(u.game.Board(), u.game.ToMove(), last, u.rand)
+}
+
+// stop stops the search, if any, and waits for its best move to be sent.
+func (u *UCI) stop() {
+    if u.search == nil {
+        return
+    }
+    u.search.cancel()
+    u.search.releaseMove()
+    <-u.search.done
+    u.search = nil
+}
+
+// ponderHit handles "ponderhit": the opponent played the move the engine
+// pondered on, so the search goes on with the clock running.
+func (u *UCI) ponderHit() {
+    if u.search == nil || u.search.time == nil || !u.search.time.Pondering() {
+        return
+    }
+    u.search.time.PonderHit()
+    u.search.releaseMove()
+}
+
+// report sends the lines found by a search, numbered from the best.
+func (u *UCI) report(board *chess.Board, r engine.Result, elapsed time.Duration) {
+    for i, l := range r.Lines {
+        u.send("info depth %d multipv %d score %v nodes %d time %d pv %v", l.Depth, i+1, score(l.Score), r.Nodes, elapsed.Milliseconds(), line(board, l.Moves))
+    }
+}
+
+func (u *UCI) bestMove(board *chess.Board, r engine.Result) {
+    if r.Move == (chess.Move{}) {
+        // no legal move; the protocol still wants an answer
+        u.send("bestmove 0000")
+        return
+    }
+    if u.ponder && len(r.PV) > 1 {
+        u.send("bestmove %v ponder %v", board.UCI(r.Move), board.UCI(r.PV[1]))
+        return
+    }
+    u.send("bestmove %v", board.UCI(r.Move))
+}
+
+// score writes a score in centipawns, or as "mate n" in moves, negative
+// when the engine is being mated.
+func score(s int) string {
+    switch {
+    case s > engine.MateScore-engine.MaxPly:
+        return fmt.Sprintf("mate %d", (engine.MateScore-s+1)/2)
+    case s < -(engine.MateScore - engine.MaxPly):
+        return fmt.Sprintf("mate %d", -(engine.MateScore+s)/2)
+    }
+    return fmt.Sprintf("cp %d", s)
+}
+
+func line(board *chess.Board, moves []chess.Move) string {
+    s := make([]string, len(moves))
+    for i, m := range moves {
+        s[i] = board.UCI(m)
+    }
+    return strings.Join(s, " ")
+}
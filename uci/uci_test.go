@@ -0,0 +1,351 @@
+package uci
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+    "goChess/book"
+    "goChess/engine"
+    "github.com/stretchr/testify/require"
+)
+
+// session talks to a UCI engine running in the background, as a GUI does.
+type session struct {
+    t     *testing.T
+    in    *io.PipeWriter
+    lines chan string
+    done  chan error
+}
+
+func start(t *testing.T) *session {
+    inR, inW := io.Pipe()
+    outR, outW := io.Pipe()
+    s := &session{t: t, in: inW, lines: make(chan string, 1024), done: make(chan error, 1)}
+
+    go func() {
+        err := New(outW).Run(inR)
+        outW.Close()
+        s.done <- err
+    }()
+    go func() {
+        scanner := bufio.NewScanner(outR)
+        for scanner.Scan() {
+            s.lines <- scanner.Text()
+        }
+        close(s.lines)
+    }()
+
+    t.Cleanup(func() {
+        inW.Close()
+        for range s.lines {
+        }
+    })
+    return s
+}
+
+func (s *session) send(commands ...string) {
+    for _, c := range commands {
+        _, err := io.WriteString(s.in, c+"\n")
+        require.NoError(s.t, err)
+    }
+}
+
+// expect reads lines until one starts with prefix, and returns it along
+// with the lines read before it.
+func (s *session) expect(prefix string) (string, []string) {
+    var before []string
+    timeout := time.After(30 * time.Second)
+    for {
+        select {
+        case l, ok := <-s.lines:
+            require.True(s.t, ok, "output ended waiting for %q", prefix)
+            if strings.HasPrefix(l, prefix) {
+                return l, before
+            }
+            before = append(before, l)
+        case <-timeout:
+            require.FailNow(s.t, "timed out", "waiting for %q", prefix)
+        }
+    }
+}
+
+// noMove checks that no best move is sent for a while. Info lines may
+// come as the search goes on.
+func (s *session) noMove(d time.Duration) {
+    timeout := time.After(d)
+    for {
+        select {
+        case l := <-s.lines:
+            require.False(s.t, strings.HasPrefix(l, "bestmove"), l)
+        case <-timeout:
+            return
+        }
+    }
+}
+
+func TestIdentify(t *testing.T) {
+    s := start(t)
+    s.send("uci")
+    _, before := s.expect("uciok")
+    require.Contains(t, before, "id name goChess")
+    require.Contains(t, before, "option name Hash type spin default 16 min 1 max 4096")
+    require.Contains(t, before, "option name Threads type spin default 1 min 1 max 256")
+    require.Contains(t, before, "option name MultiPV type spin default 1 min 1 max 256")
+    require.Contains(t, before, "option name Ponder type check default false")
+    require.Contains(t, before, "option name OwnBook type check default false")
+    require.Contains(t, before, "option name BookFile type string default <empty>")
+    require.Contains(t, before, "option name UCI_Chess960 type check default false")
+
+    s.send("isready")
+    s.expect("readyok")
+}
+
+func TestSearch(t *testing.T) {
+    s := start(t)
+    s.send("position startpos moves e2e4 e7e5", "go depth 2")
+    l, before := s.expect("bestmove")
+    require.Regexp(t, `^bestmove [a-h][1-8][a-h][1-8]$`, l)
+    require.Regexp(t, `^info depth 2 multipv 1 score cp -?\d+ nodes \d+ time \d+ pv \S+ \S+$`, before[len(before)-1])
+
+    s.send("position fen 6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", "go depth 2")
+    l, before = s.expect("bestmove")
+    require.Equal(t, "bestmove a1a8", l)
+    require.Contains(t, before[len(before)-1], "score mate 1 ")
+
+    // mated: no move to give
+    s.send("position fen R5k1/5ppp/8/8/8/8/8/6K1 b - - 0 1", "go depth 2")
+    l, _ = s.expect("bestmove")
+    require.Equal(t, "bestmove 0000", l)
+
+    s.send("ucinewgame", "go nodes 500")
+    s.expect("bestmove")
+
+    s.send("setoption name Threads value 3", "position fen 6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", "go depth 2")
+    l, _ = s.expect("bestmove")
+    require.Equal(t, "bestmove a1a8", l)
+}
+
+func TestMultiPV(t *testing.T) {
+    s := start(t)
+    s.send("setoption name MultiPV value 3", "position fen 4k3/8/2p5/3q4/8/4N3/8/3QK3 w - - 0 1", "go depth 3")
+    l, before := s.expect("bestmove")
+    require.Equal(t, "bestmove e3d5", l)
+    // three lines for each of the three iterations, as they complete
+    require.Len(t, before, 9)
+    for i, info := range before {
+        require.True(t, strings.HasPrefix(info, fmt.Sprintf("info depth %d multipv %d ", i/3+1, i%3+1)), info)
+    }
+    require.Contains(t, before[6], " pv e3d5")
+
+    // the king has two moves, so there are only two lines
+    s.send("position fen k7/8/8/2Q5/8/8/8/7K b - - 0 1", "go depth 2")
+    _, before = s.expect("bestmove")
+    require.Len(t, before, 4)
+}
+
+func TestInfinite(t *testing.T) {
+    s := start(t)
+    s.send("position startpos", "go infinite")
+    s.send("isready")
+    s.expect("readyok")
+    s.noMove(100 * time.Millisecond)
+
+    s.send("stop")
+    s.expect("bestmove")
+
+    // the search is stopped when the GUI quits
+    s.send("go infinite", "quit")
+    s.expect("bestmove")
+    require.NoError(t, <-s.done)
+}
+
+func TestInfoWhileSearching(t *testing.T) {
+    s := start(t)
+    s.send("position startpos", "go infinite")
+    // the first iterations are reported long before the search is stopped
+    l, _ := s.expect("info depth 1 ")
+    require.Contains(t, l, " pv ")
+    s.expect("info depth 2 ")
+    s.send("stop")
+    s.expect("bestmove")
+}
+
+func TestClock(t *testing.T) {
+    s := start(t)
+    begin := time.Now()
+    s.send("position startpos", "go movetime 200")
+    s.expect("bestmove")
+    require.Less(t, time.Since(begin), 2*time.Second)
+
+    begin = time.Now()
+    s.send("position startpos moves e2e4", "go wtime 3000 btime 3000 winc 10 binc 10 movestogo 20")
+    s.expect("bestmove")
+    require.Less(t, time.Since(begin), 2*time.Second)
+}
+
+func TestPonder(t *testing.T) {
+    s := start(t)
+    s.send("setoption name Ponder value true", "position startpos moves e2e4", "go ponder wtime 3000 btime 3000")
+    // pondering runs until the GUI says the move was played
+    s.noMove(300 * time.Millisecond)
+    s.send("ponderhit")
+    l, _ := s.expect("bestmove")
+    require.Regexp(t, `^bestmove \S+ ponder \S+$`, l)
+
+    s.send("go ponder wtime 3000 btime 3000")
+    s.noMove(100 * time.Millisecond)
+    s.send("stop")
+    s.expect("bestmove")
+
+    // a ponderhit without a pondering search does nothing
+    s.send("ponderhit", "isready")
+    _, before := s.expect("readyok")
+    require.Empty(t, before)
+
+    s.send("setoption name Ponder value false", "go depth 3")
+    l, _ = s.expect("bestmove")
+    require.NotContains(t, l, "ponder")
+}
+
+func TestOwnBook(t *testing.T) {
+    b, _, err := book.BuildFromPGN(strings.NewReader("1. e4 e5 2. Nf3 Nc6 *\n"), book.BuildOptions{})
+    require.NoError(t, err)
+    path := filepath.Join(t.TempDir(), "book.bin")
+    f, err := os.Create(path)
+    require.NoError(t, err)
+    require.NoError(t, b.Write(f))
+    require.NoError(t, f.Close())
+
+    s := start(t)
+    // the book is only used once OwnBook is on
+    s.send("setoption name BookFile value "+path, "position startpos", "go depth 1")
+    _, before := s.expect("bestmove")
+    require.NotEmpty(t, before)
+
+    s.send("setoption name OwnBook value true", "go depth 6")
+    l, before := s.expect("bestmove")
+    require.Equal(t, "bestmove e2e4", l)
+    require.Empty(t, before)
+
+    s.send("position startpos moves e2e4 e7e5", "go wtime 1000 btime 1000")
+    l, _ = s.expect("bestmove")
+    require.Equal(t, "bestmove g1f3", l)
+
+    // out of the book, the engine searches
+    s.send("position startpos moves d2d4", "go depth 1")
+    _, before = s.expect("bestmove")
+    require.NotEmpty(t, before)
+
+    s.send("setoption name BookFile value <empty>", "position startpos", "go depth 1")
+    _, before = s.expect("bestmove")
+    require.NotEmpty(t, before)
+
+    s.send("setoption name BookFile value "+filepath.Join(t.TempDir(), "missing.bin"))
+    l, _ = s.expect("info string")
+    require.Contains(t, l, "BookFile")
+}
+
+func TestChess960(t *testing.T) {
+    u := New(io.Discard)
+    italian := "position startpos moves e2e4 e7e5 g1f3 b8c6 f1c4 g8f6 "
+    require.True(t, u.execute(italian+"e1g1"))
+    require.Equal(t, "r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQ1RK1 b kq - 0 1", u.game.Board().FEN(u.game.ToMove()))
+    require.NoError(t, u.setPosition(strings.Fields(italian + "e1g1")[1:]))
+    require.Error(t, u.setPosition(strings.Fields(italian + "e1h1")[1:]))
+    xfen := "fen rbbknnqr/pppppppp/8/8/8/8/PPPPPPPP/RBBKNNQR w KQkq - 0 1"
+    require.Error(t, u.setPosition(strings.Fields(xfen)))
+
+    // with Chess960 castling the king takes its rook
+    require.True(t, u.execute("setoption name UCI_Chess960 value true"))
+    require.Error(t, u.setPosition(strings.Fields(italian + "e1g1")[1:]))
+    require.NoError(t, u.setPosition(strings.Fields(italian + "e1h1")[1:]))
+    require.Equal(t, "r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQ1RK1 b kq - 0 1", u.game.Board().FEN(u.game.ToMove()))
+
+    // a Chess960 setup with Shredder castling rights, castling queenside
+    require.NoError(t, u.setPosition(strings.Fields("fen nrkbbqrn/pppppppp/8/8/8/8/PPPPPPPP/NRKBBQRN w GBgb - 0 1 moves a1b3 a8b6 b3a5 b6a4 c2c3 c7c6 d1c2 d8c7 c1b1")))
+    require.True(t, strings.HasPrefix(u.game.Board().FEN(u.game.ToMove()), "1rk1bqrn/ppbppppp/2p5/N7/n7/2P5/PPBPPPPP/2KRBQRN b "))
+
+    // an X-FEN setup with the rooks in the corners, castling kingside
+    require.NoError(t, u.setPosition(strings.Fields(xfen+" moves e1d3 e8d6 f1e3 f8e6 g2g3 g7g6 g1g2 g8g7 d1h1")))
+    require.True(t, strings.HasPrefix(u.game.Board().FEN(u.game.ToMove()), "rbbk3r/ppppppqp/3nn1p1/8/8/3NN1P1/PPPPPPQP/RBB2RK1 b kq "))
+
+    s := start(t)
+    s.send("setoption name UCI_Chess960 value true", "position startpos moves e2e4 e7e5 g1f3 b8c6 f1c4 g8f6", "go depth 2")
+    l, _ := s.expect("bestmove")
+    require.NotEqual(t, "bestmove e1g1", l)
+}
+
+func TestUnderpromotion(t *testing.T) {
+    u := New(io.Discard)
+    require.NoError(t, u.setPosition(strings.Fields("fen 8/4P3/8/8/8/8/k7/4K3 w - - 0 1 moves e7e8n")))
+    require.Equal(t, "4N3/8/8/8/8/8/k7/4K3 b - - 0 1", u.game.Board().FEN(u.game.ToMove()))
+}
+
+func TestErrors(t *testing.T) {
+    s := start(t)
+    for _, c := range []string{
+        "frobnicate",
+        "setoption name Hash value lots",
+        "setoption name Hash value 0",
+        "setoption name Threads value 257",
+        "setoption name MultiPV value none",
+        "setoption name Ponder value maybe",
+        "setoption name UCI_Chess960 value 1",
+        "setoption name OwnBook value yes",
+        "setoption name Colour value blue",
+        "position startpos moves e2e5",
+        "position fen 8/8/8 w",
+        "position somewhere",
+        "go depth",
+        "go sideways",
+        "go sideways 1",
+        "go wtime soon",
+    } {
+        s.send(c)
+        l, _ := s.expect("info string")
+        require.NotEmpty(t, strings.TrimPrefix(l, "info string"), c)
+    }
+
+    s.send("setoption name hash value 32", "isready")
+    _, before := s.expect("readyok")
+    require.Empty(t, before)
+}
+
+func TestGoAfterBadPosition(t *testing.T) {
+    s := start(t)
+    for _, position := range []string{
+        "position fen 8/8/8 w",
+        "position startpos moves e2e5",
+    } {
+        s.send("position startpos moves e2e4", position)
+        s.expect("info string Invalid position")
+        s.send("go depth 2")
+        l, _ := s.expect("info string")
+        require.Contains(t, l, "No position to search")
+        l, _ = s.expect("bestmove")
+        require.Equal(t, "bestmove 0000", l)
+    }
+
+    // until a good position comes
+    s.send("position startpos", "go depth 1")
+    l, _ := s.expect("bestmove")
+    require.NotEqual(t, "bestmove 0000", l)
+
+    s.send("position somewhere", "ucinewgame", "go depth 1")
+    s.expect("info string Invalid position")
+    l, _ = s.expect("bestmove")
+    require.NotEqual(t, "bestmove 0000", l)
+}
+
+func TestScore(t *testing.T) {
+    require.Equal(t, "cp 35", score(35))
+    require.Equal(t, "mate 1", score(engine.MateScore-1))
+    require.Equal(t, "mate 2", score(engine.MateScore-3))
+    require.Equal(t, "mate -1", score(-(engine.MateScore-2)))
+    require.Equal(t, "mate 0", score(-engine.MateScore))
+}